@@ -15,10 +15,18 @@ import (
 	"github.com/danielgraviet/infertrace/internal/queryapi"
 	"github.com/danielgraviet/infertrace/internal/store"
 	infertracepb "github.com/danielgraviet/infertrace/proto"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := runReplay(os.Args[2:]); err != nil {
+			log.Fatalf("replay failed: %v", err)
+		}
+		return
+	}
+
 	const grpcAddress = ":4317"
 	const httpAddress = ":8080"
 	const workerCount = 4
@@ -30,18 +38,35 @@ func main() {
 	}
 
 	latencyStore := store.NewLatencyStore(30*time.Minute, 10000)
-	pipeline := collector.NewCollector(workerCount, queueSize, func(span collector.Span) {
+	recordLatency := func(span *collector.Span) {
 		latencyStore.Add(span.ModelName, span.StartTimeUnixNano, span.DurationNanos)
-	})
+	}
+	spanStore := collector.NewSpanStore()
+	pipeline := collector.NewCollector(workerCount, queueSize, recordLatency)
+	nameLimiter := collector.NewNameLimiter(100)
+	processor := collector.NewBatchProcessor(pipeline.WrapExporter(nameLimiter.Wrap(collector.ExporterFunc(func(_ context.Context, spans []*collector.Span) error {
+		for _, span := range spans {
+			recordLatency(span)
+		}
+		return nil
+	}))), 512, 5*time.Second)
+	receiver := collector.NewHTTPReceiver(spanStore, collector.WithProcessor(processor))
 	grpcServer := grpc.NewServer()
 	infertracepb.RegisterCollectorServiceServer(grpcServer, collector.NewServer(pipeline))
+	coltracepb.RegisterTraceServiceServer(grpcServer, collector.NewOTLPTraceServer(spanStore))
+	health := collector.NewHealth()
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", health.Handler())
+	mux.Handle("/readyz", health.Handler())
+	mux.Handle("/v1/", receiver.Handler())
+	mux.Handle("/", queryapi.NewServer(latencyStore).Handler())
 	httpServer := &http.Server{
 		Addr:    httpAddress,
-		Handler: queryapi.NewServer(latencyStore).Handler(),
+		Handler: mux,
 	}
 
 	fmt.Printf("collector listening on %s (gRPC)\n", grpcAddress)
-	fmt.Printf("query API listening on %s (HTTP)\n", httpAddress)
+	fmt.Printf("query API and span receiver listening on %s (HTTP)\n", httpAddress)
 
 	go func() {
 		if serveErr := grpcServer.Serve(lis); serveErr != nil {
@@ -54,17 +79,23 @@ func main() {
 		}
 	}()
 
+	health.MarkReady()
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	<-sigCh
 
 	log.Println("shutdown signal received, stopping collector")
+	health.MarkShuttingDown()
 	grpcServer.GracefulStop()
 	httpCtx, httpCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer httpCancel()
 	if err := httpServer.Shutdown(httpCtx); err != nil {
 		log.Printf("HTTP server shutdown error: %v", err)
 	}
+	if err := receiver.Shutdown(httpCtx); err != nil {
+		log.Printf("span receiver shutdown error: %v", err)
+	}
 	stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := pipeline.Stop(stopCtx); err != nil {
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/danielgraviet/infertrace/internal/collector"
+)
+
+// runReplay implements `collector replay [-zipkin url] [-rebase] file.ndjson`.
+// Spans are written to stdout unless a Zipkin endpoint is given.
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	zipkinURL := fs.String("zipkin", "", "Zipkin endpoint to export to (default: stdout)")
+	rebase := fs.Bool("rebase", false, "shift timestamps so the first span starts now")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: collector replay [-zipkin url] [-rebase] <file>")
+	}
+
+	var in io.Reader = os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var exporter collector.Exporter = collector.NewStdoutExporter(os.Stdout)
+	if *zipkinURL != "" {
+		exporter = collector.NewZipkinExporter(*zipkinURL, nil)
+	}
+	var opts []collector.ReplayOption
+	if *rebase {
+		opts = append(opts, collector.WithRebaseTime(time.Now()))
+	}
+
+	stats, err := collector.Replay(context.Background(), in, exporter, opts...)
+	fmt.Fprintf(os.Stderr, "replayed %d spans, %d bad lines\n", stats.Exported, len(stats.BadLines))
+	if len(stats.BadLines) > 0 {
+		fmt.Fprintf(os.Stderr, "bad lines: %v\n", stats.BadLines)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+const TruncatedSuffix = "...[truncated]"
+
+var (
+	ErrAttributeLimitReached    = errors.New("span attribute limit reached")
+	ErrUnsupportedAttributeType = errors.New("unsupported attribute type")
+)
+
+// AttributeLimits bounds what SetAttribute will store. A zero field means
+// unlimited.
+type AttributeLimits struct {
+	// MaxCount caps the number of distinct keys on a span. Overwriting an
+	// existing key is always allowed.
+	MaxCount int
+	// MaxValueLength caps the byte length of string values (and of each element
+	// of a []string); longer values are cut and suffixed with TruncatedSuffix.
+	MaxValueLength int
+}
+
+var DefaultAttributeLimits = AttributeLimits{MaxCount: 128}
+
+// WithAttributeLimits replaces DefaultAttributeLimits for this span.
+func WithAttributeLimits(limits AttributeLimits) SpanOption {
+	return func(s *Span) {
+		s.limits = &limits
+	}
+}
+
+func (s *Span) attributeLimits() AttributeLimits {
+	if s.limits == nil {
+		return DefaultAttributeLimits
+	}
+	return *s.limits
+}
+
+// normalizeAttributeValue coerces value to one of the supported attribute types:
+// string, int64, float64, bool or []string.
+func normalizeAttributeValue(value any, maxLen int) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return truncateString(v, maxLen), nil
+	case bool, int64, float64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case float32:
+		return float64(v), nil
+	case []string:
+		out := make([]string, len(v))
+		for i, elem := range v {
+			out[i] = truncateString(elem, maxLen)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%w %T", ErrUnsupportedAttributeType, value)
+	}
+}
+
+func truncateString(s string, maxLen int) string {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + TruncatedSuffix
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetAttribute_TruncatesLongStrings(t *testing.T) {
+	span := &Span{}
+	WithAttributeLimits(AttributeLimits{MaxValueLength: 5})(span)
+
+	if err := span.SetAttribute("prompt", strings.Repeat("x", 20)); err != nil {
+		t.Fatalf("SetAttribute returned error: %v", err)
+	}
+	if got, _ := span.GetAttribute("prompt"); got != "xxxxx"+TruncatedSuffix {
+		t.Fatalf("prompt = %q, want truncated", got)
+	}
+
+	if err := span.SetAttribute("tags", []string{"short", "much-too-long"}); err != nil {
+		t.Fatalf("SetAttribute returned error: %v", err)
+	}
+	want := []string{"short", "much-" + TruncatedSuffix}
+	if got, _ := span.GetAttribute("tags"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("tags = %v, want %v", got, want)
+	}
+
+	if err := span.SetAttribute("emoji", "aé"); err != nil {
+		t.Fatalf("SetAttribute returned error: %v", err)
+	}
+	WithAttributeLimits(AttributeLimits{MaxValueLength: 2})(span)
+	_ = span.SetAttribute("emoji", "aé")
+	if got, _ := span.GetAttribute("emoji"); got != "a"+TruncatedSuffix {
+		t.Fatalf("emoji = %q, want cut at rune boundary", got)
+	}
+}
+
+func TestSetAttribute_CountCapDropsNewKeys(t *testing.T) {
+	span := &Span{}
+	WithAttributeLimits(AttributeLimits{MaxCount: 2})(span)
+
+	_ = span.SetAttribute("a", "1")
+	_ = span.SetAttribute("b", "2")
+	if err := span.SetAttribute("c", "3"); !errors.Is(err, ErrAttributeLimitReached) {
+		t.Fatalf("SetAttribute(c) error = %v, want %v", err, ErrAttributeLimitReached)
+	}
+	if _, ok := span.GetAttribute("c"); ok {
+		t.Fatalf("key past the cap was stored")
+	}
+	if err := span.SetAttribute("a", "updated"); err != nil {
+		t.Fatalf("overwriting an existing key returned error: %v", err)
+	}
+}
+
+func TestSetAttribute_TypeConstraints(t *testing.T) {
+	span := &Span{}
+
+	tests := []struct {
+		value any
+		want  any
+	}{
+		{value: 7, want: int64(7)},
+		{value: uint16(7), want: int64(7)},
+		{value: float32(1.5), want: float64(1.5)},
+		{value: true, want: true},
+	}
+	for _, tt := range tests {
+		if err := span.SetAttribute("k", tt.value); err != nil {
+			t.Fatalf("SetAttribute(%T) returned error: %v", tt.value, err)
+		}
+		if got, _ := span.GetAttribute("k"); got != tt.want {
+			t.Fatalf("SetAttribute(%T) stored %#v, want %#v", tt.value, got, tt.want)
+		}
+	}
+
+	err := span.SetAttribute("bad", map[string]int{"x": 1})
+	if !errors.Is(err, ErrUnsupportedAttributeType) {
+		t.Fatalf("SetAttribute(map) error = %v, want %v", err, ErrUnsupportedAttributeType)
+	}
+	if _, ok := span.GetAttribute("bad"); ok {
+		t.Fatalf("unsupported value was stored")
+	}
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Baggage is W3C baggage: key/value pairs that travel with a trace across
+// service boundaries, separate from any one span's attributes.
+type Baggage map[string]string
+
+type baggageContextKey struct{}
+
+func (b Baggage) Set(key, value string) {
+	b[key] = value
+}
+
+func (b Baggage) Get(key string) (string, bool) {
+	value, ok := b[key]
+	return value, ok
+}
+
+// Encode renders the baggage header value. Keys are sorted so the output is
+// stable, and values are percent-encoded as the spec requires.
+func (b Baggage) Encode() string {
+	keys := make([]string, 0, len(b))
+	for key := range b {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+url.PathEscape(b[key]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseBaggage decodes a baggage header. Entries without a key, without "=",
+// or with a bad percent-encoding are skipped; the rest are kept. Entry
+// properties (after ";") are ignored.
+func ParseBaggage(header string) Baggage {
+	b := make(Baggage)
+	for _, entry := range strings.Split(header, ",") {
+		entry, _, _ = strings.Cut(entry, ";")
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		b[key] = decoded
+	}
+	return b
+}
+
+func ContextWithBaggage(ctx context.Context, b Baggage) context.Context {
+	return context.WithValue(ctx, baggageContextKey{}, b)
+}
+
+func BaggageFromContext(ctx context.Context) (Baggage, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	b, ok := ctx.Value(baggageContextKey{}).(Baggage)
+	return b, ok
+}
@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestBaggage_EncodeDecodeRoundTrip(t *testing.T) {
+	b := make(Baggage)
+	b.Set("tenant.id", "acme")
+	b.Set("experiment", "prompt v2, beam=4")
+
+	header := b.Encode()
+	if header != "experiment=prompt%20v2%2C%20beam=4,tenant.id=acme" {
+		t.Fatalf("encoded header = %q", header)
+	}
+
+	decoded := ParseBaggage(header)
+	if !reflect.DeepEqual(decoded, b) {
+		t.Fatalf("round trip mismatch: got=%v want=%v", decoded, b)
+	}
+	if got, ok := decoded.Get("tenant.id"); !ok || got != "acme" {
+		t.Fatalf("tenant.id = %q (ok=%v), want acme", got, ok)
+	}
+}
+
+func TestParseBaggage_SkipsMalformedEntries(t *testing.T) {
+	decoded := ParseBaggage("tenant.id=acme, novalue ,=orphan,bad=%zz,region=us-east;ttl=60")
+
+	want := Baggage{"tenant.id": "acme", "region": "us-east"}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Fatalf("decoded = %v, want %v", decoded, want)
+	}
+}
+
+func TestBaggageContext(t *testing.T) {
+	if _, ok := BaggageFromContext(context.Background()); ok {
+		t.Fatalf("expected no baggage in empty context")
+	}
+
+	b := Baggage{"tenant.id": "acme"}
+	got, ok := BaggageFromContext(ContextWithBaggage(context.Background(), b))
+	if !ok || got["tenant.id"] != "acme" {
+		t.Fatalf("baggage from context = %v (ok=%v)", got, ok)
+	}
+}
@@ -0,0 +1,198 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxBatchSize  = 512
+	defaultFlushInterval = 5 * time.Second
+)
+
+var ErrProcessorStopped = errors.New("batch processor is stopped")
+
+// BatchProcessor buffers spans and hands them to an Exporter in batches, either
+// when maxBatchSize spans are waiting or every flushInterval, whichever is first.
+type BatchProcessor struct {
+	exporter      Exporter
+	maxBatchSize  int
+	flushInterval time.Duration
+	resource      Resource
+	minDuration   time.Duration
+	onDrop        DropFunc
+
+	mu      sync.Mutex
+	buffer  []*Span
+	stopped bool
+
+	// exportMu keeps batches reaching the exporter one at a time and in order.
+	exportMu sync.Mutex
+
+	// ctx bounds exports started by Add and the flush timer. Shutdown cancels
+	// it, so a hung downstream cannot outlive the processor.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	stopCh   chan struct{}
+	loopDone chan struct{}
+	stopOnce sync.Once
+}
+
+// DropFasterThan makes the processor discard completed spans shorter than d,
+// e.g. sub-microsecond cache hits. Error spans and spans that have not ended
+// are always kept.
+func DropFasterThan(d time.Duration) ProcessorOption {
+	return func(p *BatchProcessor) {
+		p.minDuration = d
+	}
+}
+
+// WithProcessorOnDrop calls fn for every span Add discards, with DropSampled
+// or DropTooFast.
+func WithProcessorOnDrop(fn DropFunc) ProcessorOption {
+	return func(p *BatchProcessor) {
+		p.onDrop = fn
+	}
+}
+
+func NewBatchProcessor(exporter Exporter, maxBatchSize int, flushInterval time.Duration, opts ...ProcessorOption) *BatchProcessor {
+	if maxBatchSize <= 0 {
+		maxBatchSize = defaultMaxBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &BatchProcessor{
+		ctx:           ctx,
+		cancel:        cancel,
+		exporter:      exporter,
+		maxBatchSize:  maxBatchSize,
+		flushInterval: flushInterval,
+		buffer:        make([]*Span, 0, maxBatchSize),
+		stopCh:        make(chan struct{}),
+		loopDone:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	go p.loop()
+	return p
+}
+
+func (p *BatchProcessor) loop() {
+	defer close(p.loopDone)
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = p.Flush(p.ctx)
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// Add buffers span for export. Unsampled spans, and spans filtered by
+// DropFasterThan, are dropped silently.
+func (p *BatchProcessor) Add(span *Span) error {
+	if span == nil {
+		return nil
+	}
+	span.mu.Lock()
+	sampled, tooFast := span.Sampled, p.tooFastLocked(span)
+	keep := sampled && !tooFast
+	if keep && p.resource != nil && span.Resource == nil {
+		span.Resource = p.resource
+	}
+	span.mu.Unlock()
+	switch {
+	case !sampled:
+		p.onDrop.call(span, DropSampled)
+		return nil
+	case tooFast:
+		p.onDrop.call(span, DropTooFast)
+		return nil
+	}
+
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return ErrProcessorStopped
+	}
+	p.buffer = append(p.buffer, span)
+	var batch []*Span
+	if len(p.buffer) >= p.maxBatchSize {
+		batch = p.takeLocked()
+	}
+	p.mu.Unlock()
+
+	if batch == nil {
+		return nil
+	}
+	return p.export(p.ctx, batch)
+}
+
+// tooFastLocked reports whether span is a completed, non-error span under the
+// DropFasterThan threshold. The caller holds span.mu.
+func (p *BatchProcessor) tooFastLocked(span *Span) bool {
+	if p.minDuration <= 0 || span.Status == StatusError {
+		return false
+	}
+	if !span.ended && span.DurationNanos == 0 {
+		return false
+	}
+	return time.Duration(span.DurationNanos) < p.minDuration
+}
+
+// Flush exports whatever is currently buffered.
+func (p *BatchProcessor) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	batch := p.takeLocked()
+	p.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return p.export(ctx, batch)
+}
+
+// Shutdown stops the flush timer, rejects further Adds and exports the
+// remaining partial batch. If ctx ends first, in-flight and final exports are
+// cancelled and the context error is returned.
+func (p *BatchProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		p.mu.Lock()
+		p.stopped = true
+		p.mu.Unlock()
+		close(p.stopCh)
+	})
+	stop := context.AfterFunc(ctx, p.cancel)
+	defer stop()
+	defer p.cancel()
+
+	<-p.loopDone
+	return p.Flush(ctx)
+}
+
+func (p *BatchProcessor) takeLocked() []*Span {
+	if len(p.buffer) == 0 {
+		return nil
+	}
+	batch := p.buffer
+	p.buffer = make([]*Span, 0, p.maxBatchSize)
+	return batch
+}
+
+func (p *BatchProcessor) export(ctx context.Context, batch []*Span) error {
+	p.exportMu.Lock()
+	defer p.exportMu.Unlock()
+	return p.exporter.Export(ctx, batch)
+}
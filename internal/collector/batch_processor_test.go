@@ -0,0 +1,187 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	batches [][]*Span
+	err     error
+}
+
+func (e *recordingExporter) Export(_ context.Context, spans []*Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	batch := make([]*Span, len(spans))
+	copy(batch, spans)
+	e.batches = append(e.batches, batch)
+	return e.err
+}
+
+func (e *recordingExporter) Batches() [][]*Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([][]*Span, len(e.batches))
+	copy(out, e.batches)
+	return out
+}
+
+func (e *recordingExporter) SpanCount() int {
+	count := 0
+	for _, batch := range e.Batches() {
+		count += len(batch)
+	}
+	return count
+}
+
+func TestBatchProcessor_FlushesWhenBatchIsFull(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, 3, time.Hour)
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+	for i := range 7 {
+		if err := p.Add(&Span{SpanID: string(rune('a' + i)), Sampled: true}); err != nil {
+			t.Fatalf("Add %d returned error: %v", i, err)
+		}
+	}
+
+	batches := exporter.Batches()
+	if got, want := len(batches), 2; got != want {
+		t.Fatalf("batch count = %d, want %d", got, want)
+	}
+	for i, batch := range batches {
+		if len(batch) != 3 {
+			t.Fatalf("batch %d size = %d, want 3", i, len(batch))
+		}
+	}
+	if batches[1][0].SpanID != "d" {
+		t.Fatalf("second batch starts with %q, want d", batches[1][0].SpanID)
+	}
+}
+
+func TestBatchProcessor_FlushesOnInterval(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, 100, 10*time.Millisecond)
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+	if err := p.Add(&Span{SpanID: "a", Sampled: true}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for exporter.SpanCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for interval flush")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchProcessor_ShutdownDrainsPartialBatch(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, 100, time.Hour)
+
+	for i := range 5 {
+		if err := p.Add(&Span{SpanID: string(rune('a' + i)), Sampled: true}); err != nil {
+			t.Fatalf("Add %d returned error: %v", i, err)
+		}
+	}
+	if got := exporter.SpanCount(); got != 0 {
+		t.Fatalf("exported %d spans before shutdown, want 0", got)
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	if got, want := exporter.SpanCount(), 5; got != want {
+		t.Fatalf("exported = %d, want %d", got, want)
+	}
+
+	if err := p.Add(&Span{SpanID: "late", Sampled: true}); err != ErrProcessorStopped {
+		t.Fatalf("Add after shutdown = %v, want %v", err, ErrProcessorStopped)
+	}
+}
+
+func TestBatchProcessor_ManualFlush(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, 100, time.Hour)
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+	_ = p.Add(&Span{SpanID: "a", Sampled: true})
+	_ = p.Add(&Span{SpanID: "b", Sampled: true})
+	if err := p.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if got, want := exporter.SpanCount(), 2; got != want {
+		t.Fatalf("exported = %d, want %d", got, want)
+	}
+}
+
+func TestBatchProcessor_ShutdownDeadlineCancelsExport(t *testing.T) {
+	exporter := ExporterFunc(func(ctx context.Context, _ []*Span) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	p := NewBatchProcessor(exporter, 10, time.Hour)
+	if err := p.Add(&Span{SpanID: "a", Sampled: true}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Shutdown took %v, want prompt return", elapsed)
+	}
+}
+
+func TestBatchProcessor_SkipsUnsampledSpans(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, 10, time.Hour)
+
+	unsampled, _ := NewSpan("api", "predict", "gpt-4o-mini", WithSampler(NewProbabilitySampler(0)))
+	sampled, _ := NewSpan("api", "predict", "gpt-4o-mini")
+	_ = p.Add(unsampled)
+	_ = p.Add(sampled)
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	batches := exporter.Batches()
+	if exporter.SpanCount() != 1 || batches[0][0] != sampled {
+		t.Fatalf("exported %v, want only the sampled span", batches)
+	}
+}
+
+func TestBatchProcessor_DropFasterThan(t *testing.T) {
+	exporter := &recordingExporter{}
+	p := NewBatchProcessor(exporter, 10, time.Hour, DropFasterThan(time.Microsecond))
+
+	fast := &Span{SpanID: "fast", Sampled: true, DurationNanos: 500}
+	fastErr := &Span{SpanID: "fast-error", Sampled: true, DurationNanos: 500, Status: StatusError}
+	slow := &Span{SpanID: "slow", Sampled: true, DurationNanos: int64(time.Millisecond)}
+	open, _ := NewSpan("api", "predict", "gpt-4o-mini")
+	open.SpanID = "open"
+	for _, span := range []*Span{fast, fastErr, slow, open} {
+		_ = p.Add(span)
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	got := spanIDs(exporter.Batches()[0])
+	want := []string{"fast-error", "slow", "open"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("exported %v, want %v", got, want)
+	}
+}
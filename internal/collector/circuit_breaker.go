@@ -0,0 +1,117 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("exporter circuit breaker is open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerExporter stops calling a failing exporter. After threshold
+// consecutive failures it opens and fails every Export with ErrCircuitOpen for
+// cooldown, then lets a single probe batch through: success closes the
+// breaker, failure opens it for another cooldown.
+type CircuitBreakerExporter struct {
+	next      Exporter
+	threshold int
+	cooldown  time.Duration
+	clock     Clock
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	dropped  int64
+}
+
+// NewCircuitBreakerExporter opens after threshold consecutive failures;
+// threshold <= 0 is treated as 1.
+func NewCircuitBreakerExporter(next Exporter, threshold int, cooldown time.Duration) *CircuitBreakerExporter {
+	return &CircuitBreakerExporter{
+		next:      next,
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		clock:     realClock{},
+	}
+}
+
+func (b *CircuitBreakerExporter) Export(ctx context.Context, spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	if !b.allow(len(spans)) {
+		return ErrCircuitOpen
+	}
+
+	err := b.next.Export(ctx, spans)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.state = BreakerClosed
+		b.failures = 0
+		return nil
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.clock.Now()
+	}
+	return err
+}
+
+// allow reports whether a batch of n spans may reach next, counting them as
+// dropped if not.
+func (b *CircuitBreakerExporter) allow(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.clock.Now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+	}
+	switch {
+	case b.state == BreakerClosed:
+		return true
+	case b.state == BreakerHalfOpen && !b.probing:
+		b.probing = true
+		return true
+	}
+	b.dropped += int64(n)
+	return false
+}
+
+func (b *CircuitBreakerExporter) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// DroppedSpans reports how many spans were rejected while the breaker was open.
+func (b *CircuitBreakerExporter) DroppedSpans() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyExporter fails while failing is set and counts calls that reached it.
+type flakyExporter struct {
+	failing bool
+	calls   int
+}
+
+func (f *flakyExporter) Export(context.Context, []*Span) error {
+	f.calls++
+	if f.failing {
+		return errors.New("downstream unavailable")
+	}
+	return nil
+}
+
+func TestCircuitBreakerExporter_TripsFastFailsAndRecovers(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000, 0))
+	next := &flakyExporter{failing: true}
+	breaker := NewCircuitBreakerExporter(next, 3, 30*time.Second)
+	breaker.clock = clock
+	batch := []*Span{{SpanID: "a"}, {SpanID: "b"}}
+	ctx := context.Background()
+
+	for i := range 3 {
+		if err := breaker.Export(ctx, batch); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("failure %d: Export error = %v, want downstream error", i, err)
+		}
+	}
+	if got := breaker.State(); got != BreakerOpen {
+		t.Fatalf("state after 3 failures = %v, want open", got)
+	}
+
+	if err := breaker.Export(ctx, batch); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Export while open = %v, want %v", err, ErrCircuitOpen)
+	}
+	if next.calls != 3 {
+		t.Fatalf("downstream calls = %d, want 3 (open breaker must not call it)", next.calls)
+	}
+	if got := breaker.DroppedSpans(); got != 2 {
+		t.Fatalf("DroppedSpans = %d, want 2", got)
+	}
+
+	clock.Advance(30 * time.Second)
+	next.failing = false
+	if err := breaker.Export(ctx, batch); err != nil {
+		t.Fatalf("probe Export = %v, want nil", err)
+	}
+	if got := breaker.State(); got != BreakerClosed {
+		t.Fatalf("state after successful probe = %v, want closed", got)
+	}
+	if err := breaker.Export(ctx, batch); err != nil || next.calls != 5 {
+		t.Fatalf("Export after recovery = %v with %d calls, want nil with 5", err, next.calls)
+	}
+}
+
+func TestCircuitBreakerExporter_FailedProbeReopens(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000, 0))
+	next := &flakyExporter{failing: true}
+	breaker := NewCircuitBreakerExporter(next, 1, time.Minute)
+	breaker.clock = clock
+	batch := []*Span{{SpanID: "a"}}
+	ctx := context.Background()
+
+	_ = breaker.Export(ctx, batch)
+	clock.Advance(time.Minute)
+	if err := breaker.Export(ctx, batch); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe Export = %v, want downstream error", err)
+	}
+	if got := breaker.State(); got != BreakerOpen {
+		t.Fatalf("state after failed probe = %v, want open", got)
+	}
+	clock.Advance(59 * time.Second)
+	if err := breaker.Export(ctx, batch); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Export before new cooldown ends = %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+func TestCircuitBreakerExporter_SuccessResetsFailureCount(t *testing.T) {
+	next := &flakyExporter{}
+	breaker := NewCircuitBreakerExporter(next, 2, time.Minute)
+	ctx := context.Background()
+	batch := []*Span{{SpanID: "a"}}
+
+	for _, failing := range []bool{true, false, true, false} {
+		next.failing = failing
+		_ = breaker.Export(ctx, batch)
+	}
+	if got := breaker.State(); got != BreakerClosed {
+		t.Fatalf("state = %v, want closed: failures were not consecutive", got)
+	}
+}
@@ -0,0 +1,30 @@
+package collector
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock makes the span read time from clock instead of the wall clock, both
+// for its start time and for End, AddEvent and RecordError.
+func WithClock(clock Clock) SpanOption {
+	return func(s *Span) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+func (s *Span) now() time.Time {
+	if s.clock == nil {
+		return realClock{}.Now()
+	}
+	return s.clock.Now()
+}
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(start time.Time) *fakeClock {
+	return &fakeClock{now: start}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestWithClock_DeterministicDuration(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000, 0))
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithClock(clock))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if got, want := span.StartTimeUnixNano, time.Unix(1_000, 0).UnixNano(); got != want {
+		t.Fatalf("start = %d, want %d", got, want)
+	}
+
+	clock.Advance(250 * time.Millisecond)
+	span.AddEvent("first token emitted", nil)
+	clock.Advance(750 * time.Millisecond)
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+
+	if got, want := span.DurationNanos, int64(time.Second); got != want {
+		t.Fatalf("duration = %d, want %d", got, want)
+	}
+	if got, want := span.Events[0].TimeUnixNano, time.Unix(1_000, 0).Add(250*time.Millisecond).UnixNano(); got != want {
+		t.Fatalf("event time = %d, want %d", got, want)
+	}
+}
+
+func TestWithClock_ExplicitStartTimeWins(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000, 0))
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithStartTime(77), WithClock(clock))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.StartTimeUnixNano != 77 {
+		t.Fatalf("start = %d, want 77", span.StartTimeUnixNano)
+	}
+}
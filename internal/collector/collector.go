@@ -10,39 +10,93 @@ import (
 var (
 	ErrQueueFull       = errors.New("collector queue is full")
 	ErrCollectorClosed = errors.New("collector is stopped")
+	ErrRateLimited     = errors.New("span rate limited")
+
+	// ErrSampledOut is returned for spans the collector sampler dropped. The
+	// drop is intentional, so callers should not retry them.
+	ErrSampledOut = errors.New("span sampled out")
 )
 
+// Stats is a snapshot of the collector's own telemetry. Every span offered to
+// the collector is counted in Received and in exactly one of Accepted, Dropped,
+// Invalid, SampledOut or RateLimited. Accepted means queued for the sink, and
+// Enqueue returns nil only for those spans.
 type Stats struct {
-	Accepted int64
-	Dropped  int64
-	Invalid  int64
+	Received    int64
+	Accepted    int64
+	Dropped     int64
+	Invalid     int64
+	SampledOut  int64
+	RateLimited int64
+
+	// ExportedBatches and ExportFailures count calls through WrapExporter.
+	ExportedBatches int64
+	ExportFailures  int64
+
+	QueueDepth int
+}
+
+type CollectorOption func(*Collector)
+
+// WithCollectorSampler makes Enqueue drop spans whose trace sampler rejects
+// with ErrSampledOut, counting them as SampledOut.
+func WithCollectorSampler(sampler Sampler) CollectorOption {
+	return func(c *Collector) {
+		c.sampler = sampler
+	}
+}
+
+// WithCollectorRateLimiter makes Enqueue reject spans over their service's rate
+// with ErrRateLimited.
+func WithCollectorRateLimiter(limiter *RateLimiter) CollectorOption {
+	return func(c *Collector) {
+		c.limiter = limiter
+	}
+}
+
+// WithOnDrop calls fn for every span the collector drops, with the reason.
+func WithOnDrop(fn DropFunc) CollectorOption {
+	return func(c *Collector) {
+		c.onDrop = fn
+	}
 }
 
 type Collector struct {
-	queue    chan Span
+	queue    chan *Span
 	workerWg sync.WaitGroup
 	stopOnce sync.Once
 	closed   atomic.Bool
-
-	accepted atomic.Int64
-	dropped  atomic.Int64
-	invalid  atomic.Int64
-
-	sink func(Span)
+	sampler  Sampler
+	limiter  *RateLimiter
+	onDrop   DropFunc
+
+	received    atomic.Int64
+	accepted    atomic.Int64
+	dropped     atomic.Int64
+	invalid     atomic.Int64
+	sampledOut  atomic.Int64
+	rateLimited atomic.Int64
+	exported    atomic.Int64
+	exportFails atomic.Int64
+
+	sink func(*Span)
 }
 
-func NewCollector(workerCount, queueSize int, sink func(Span)) *Collector {
+func NewCollector(workerCount, queueSize int, sink func(*Span), opts ...CollectorOption) *Collector {
 	if queueSize <= 0 {
 		queueSize = 1
 	}
 	if sink == nil {
-		sink = func(Span) {}
+		sink = func(*Span) {}
 	}
 
 	c := &Collector{
-		queue: make(chan Span, queueSize),
+		queue: make(chan *Span, queueSize),
 		sink:  sink,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
 
 	for range workerCount {
 		c.workerWg.Add(1)
@@ -61,34 +115,84 @@ func (c *Collector) worker() {
 
 // Enqueue applies MVP backpressure policy: do not block callers when queue is full.
 // Instead, drop new work quickly and return ErrQueueFull so ingestion latency stays bounded.
-func (c *Collector) Enqueue(span Span) error {
+func (c *Collector) Enqueue(span *Span) error {
+	c.received.Add(1)
 	if c.closed.Load() {
 		c.dropped.Add(1)
+		c.drop(span, DropCollectorClosed)
 		return ErrCollectorClosed
 	}
 
+	if span != nil {
+		span.mu.Lock()
+		traceID, service := span.TraceID, span.ServiceName
+		span.mu.Unlock()
+		if c.sampler != nil && !c.sampler.ShouldSample(traceID) {
+			c.sampledOut.Add(1)
+			c.drop(span, DropSampled)
+			return ErrSampledOut
+		}
+		if !c.limiter.Allow(service) {
+			c.rateLimited.Add(1)
+			c.drop(span, DropRateLimited)
+			return ErrRateLimited
+		}
+	}
+
 	select {
 	case c.queue <- span:
 		c.accepted.Add(1)
 		return nil
 	default:
 		c.dropped.Add(1)
+		c.drop(span, DropQueueFull)
 		return ErrQueueFull
 	}
 }
 
+// RejectInvalid counts span as invalid, like AddInvalid(1), and reports it to
+// the drop callback.
+func (c *Collector) RejectInvalid(span *Span) {
+	c.AddInvalid(1)
+	c.drop(span, DropInvalid)
+}
+
+func (c *Collector) drop(span *Span, reason DropReason) {
+	c.onDrop.call(span, reason)
+}
+
 func (c *Collector) AddInvalid(n int) {
 	if n <= 0 {
 		return
 	}
+	c.received.Add(int64(n))
 	c.invalid.Add(int64(n))
 }
 
+// WrapExporter counts each Export call on next as an exported batch or an
+// export failure in Stats.
+func (c *Collector) WrapExporter(next Exporter) Exporter {
+	return ExporterFunc(func(ctx context.Context, spans []*Span) error {
+		if err := next.Export(ctx, spans); err != nil {
+			c.exportFails.Add(1)
+			return err
+		}
+		c.exported.Add(1)
+		return nil
+	})
+}
+
 func (c *Collector) Stats() Stats {
 	return Stats{
-		Accepted: c.accepted.Load(),
-		Dropped:  c.dropped.Load(),
-		Invalid:  c.invalid.Load(),
+		Received:        c.received.Load(),
+		Accepted:        c.accepted.Load(),
+		Dropped:         c.dropped.Load(),
+		Invalid:         c.invalid.Load(),
+		SampledOut:      c.sampledOut.Load(),
+		RateLimited:     c.rateLimited.Load(),
+		ExportedBatches: c.exported.Load(),
+		ExportFailures:  c.exportFails.Load(),
+		QueueDepth:      len(c.queue),
 	}
 }
 
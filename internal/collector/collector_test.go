@@ -2,18 +2,21 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	infertracepb "github.com/danielgraviet/infertrace/proto"
 )
 
 func TestCollector_QueueFullDrops(t *testing.T) {
 	c := NewCollector(0, 1, nil)
 
-	if err := c.Enqueue(Span{ServiceName: "svc"}); err != nil {
+	if err := c.Enqueue(&Span{ServiceName: "svc"}); err != nil {
 		t.Fatalf("first enqueue failed: %v", err)
 	}
-	if err := c.Enqueue(Span{ServiceName: "svc"}); err != ErrQueueFull {
+	if err := c.Enqueue(&Span{ServiceName: "svc"}); err != ErrQueueFull {
 		t.Fatalf("second enqueue error = %v, want %v", err, ErrQueueFull)
 	}
 
@@ -28,13 +31,13 @@ func TestCollector_QueueFullDrops(t *testing.T) {
 
 func TestCollector_StopDrainsQueueGracefully(t *testing.T) {
 	var processed atomic.Int64
-	c := NewCollector(2, 4, func(Span) {
+	c := NewCollector(2, 4, func(*Span) {
 		time.Sleep(5 * time.Millisecond)
 		processed.Add(1)
 	})
 
 	for i := range 4 {
-		if err := c.Enqueue(Span{ServiceName: "svc", SpanID: string(rune('a' + i))}); err != nil {
+		if err := c.Enqueue(&Span{ServiceName: "svc", SpanID: string(rune('a' + i))}); err != nil {
 			t.Fatalf("enqueue %d failed: %v", i, err)
 		}
 	}
@@ -56,7 +59,133 @@ func TestCollector_StopRejectsNewEnqueue(t *testing.T) {
 		t.Fatalf("Stop failed: %v", err)
 	}
 
-	if err := c.Enqueue(Span{ServiceName: "svc"}); err != ErrCollectorClosed {
+	if err := c.Enqueue(&Span{ServiceName: "svc"}); err != ErrCollectorClosed {
 		t.Fatalf("enqueue after stop = %v, want %v", err, ErrCollectorClosed)
 	}
 }
+
+// dropTraceSampler rejects exactly one trace ID.
+type dropTraceSampler string
+
+func (s dropTraceSampler) ShouldSample(traceID string) bool {
+	return traceID != string(s)
+}
+
+func TestCollector_StatsAcrossPipeline(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	limiter.clock = newFakeClock(time.Unix(0, 0))
+	pipeline := NewCollector(0, 2, nil,
+		WithCollectorSampler(dropTraceSampler("unlucky")),
+		WithCollectorRateLimiter(limiter))
+	server := NewServer(pipeline)
+
+	pbSpan := func(traceID, service string) *infertracepb.Span {
+		return &infertracepb.Span{
+			TraceId: traceID, SpanId: "s", ServiceName: service, OperationName: "predict",
+			ModelName: "gpt-4o-mini", StartTimeUnixNano: 1, DurationNanos: 1, Status: "ok",
+		}
+	}
+	req := &infertracepb.SendSpanBatchRequest{Spans: []*infertracepb.Span{
+		pbSpan("t1", "api"),
+		pbSpan("t2", "api"),
+		pbSpan("unlucky", "billing"),
+		pbSpan("t3", ""),
+		pbSpan("t4", "billing"),
+		pbSpan("t5", "tokenizer"),
+	}}
+	resp, err := server.SendSpanBatch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SendSpanBatch returned error: %v", err)
+	}
+	if resp.GetAcceptedCount() != 2 || resp.GetRejectedCount() != 4 {
+		t.Fatalf("response = %d accepted / %d rejected, want 2 / 4 matching Stats",
+			resp.GetAcceptedCount(), resp.GetRejectedCount())
+	}
+
+	failing := pipeline.WrapExporter(ExporterFunc(func(context.Context, []*Span) error {
+		return errors.New("downstream unavailable")
+	}))
+	ok := pipeline.WrapExporter(&recordingExporter{})
+	_ = failing.Export(context.Background(), []*Span{{}})
+	_ = ok.Export(context.Background(), []*Span{{}})
+	_ = ok.Export(context.Background(), []*Span{{}})
+
+	want := Stats{
+		Received:        6,
+		Accepted:        2, // t1, t4
+		Dropped:         1, // t5: queue of 2 is full
+		Invalid:         1, // t3: no service name
+		SampledOut:      1, // unlucky
+		RateLimited:     1, // t2: second api span in the same instant
+		ExportedBatches: 2,
+		ExportFailures:  1,
+		QueueDepth:      2,
+	}
+	if got := pipeline.Stats(); got != want {
+		t.Fatalf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCollector_OnDropReportsReason(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	limiter.clock = newFakeClock(time.Unix(0, 0))
+	got := map[string]DropReason{}
+	pipeline := NewCollector(0, 1, nil,
+		WithCollectorSampler(dropTraceSampler("unlucky")),
+		WithCollectorRateLimiter(limiter),
+		WithOnDrop(func(span *Span, reason DropReason) {
+			got[span.TraceID] = reason
+		}))
+	server := NewServer(pipeline)
+
+	pbSpan := func(traceID, service string) *infertracepb.Span {
+		return &infertracepb.Span{
+			TraceId: traceID, SpanId: "s", ServiceName: service, OperationName: "predict",
+			ModelName: "gpt-4o-mini", StartTimeUnixNano: 1, DurationNanos: 1, Status: "ok",
+		}
+	}
+	req := &infertracepb.SendSpanBatchRequest{Spans: []*infertracepb.Span{
+		pbSpan("t1", "api"),
+		pbSpan("t2", "api"),
+		pbSpan("unlucky", "billing"),
+		pbSpan("t3", ""),
+		pbSpan("t4", "billing"),
+	}}
+	if _, err := server.SendSpanBatch(context.Background(), req); err != nil {
+		t.Fatalf("SendSpanBatch returned error: %v", err)
+	}
+
+	want := map[string]DropReason{
+		"t2":      DropRateLimited,
+		"unlucky": DropSampled,
+		"t3":      DropInvalid,
+		"t4":      DropQueueFull,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("OnDrop calls = %v, want %v", got, want)
+	}
+	for traceID, reason := range want {
+		if got[traceID] != reason {
+			t.Fatalf("OnDrop reason for %s = %v, want %v", traceID, got[traceID], reason)
+		}
+	}
+}
+
+func TestCollector_NilOnDrop(t *testing.T) {
+	pipeline := NewCollector(0, 1, nil, WithOnDrop(nil))
+	_ = pipeline.Enqueue(&Span{})
+	if err := pipeline.Enqueue(&Span{}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Enqueue = %v, want ErrQueueFull", err)
+	}
+	pipeline.RejectInvalid(&Span{})
+}
+
+func TestCollector_EnqueueSampledOut(t *testing.T) {
+	pipeline := NewCollector(0, 1, nil, WithCollectorSampler(dropTraceSampler("unlucky")))
+	if err := pipeline.Enqueue(&Span{TraceID: "unlucky"}); !errors.Is(err, ErrSampledOut) {
+		t.Fatalf("Enqueue = %v, want %v", err, ErrSampledOut)
+	}
+	if got := pipeline.Stats(); got.SampledOut != 1 || got.Accepted != 0 {
+		t.Fatalf("Stats() = %+v, want one SampledOut and nothing Accepted", got)
+	}
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"maps"
+	"reflect"
+)
+
+// CompactTrace is a trace whose shared attributes have been factored out of
+// the individual spans. Use Decompact to get full spans back.
+type CompactTrace struct {
+	Common map[string]any `json:"common,omitempty"`
+	Spans  []*Span        `json:"spans"`
+}
+
+// Compact moves every attribute that has the same key and value on all spans
+// of a trace into CompactTrace.Common and strips it from the spans. The input
+// spans are not modified; the result holds clones. Nil spans are skipped.
+func Compact(spans []*Span) CompactTrace {
+	var out CompactTrace
+	for _, span := range spans {
+		if span != nil {
+			out.Spans = append(out.Spans, span.Clone())
+		}
+	}
+	if len(out.Spans) == 0 {
+		return out
+	}
+
+	common := maps.Clone(out.Spans[0].Attributes)
+	for _, span := range out.Spans[1:] {
+		for key, value := range common {
+			other, ok := span.Attributes[key]
+			if !ok || !reflect.DeepEqual(value, other) {
+				delete(common, key)
+			}
+		}
+	}
+	if len(common) == 0 {
+		return out
+	}
+
+	for _, span := range out.Spans {
+		for key := range common {
+			delete(span.Attributes, key)
+		}
+	}
+	out.Common = common
+	return out
+}
+
+// Decompact reverses Compact, returning clones of the spans with the common
+// attributes restored. Attributes already present on a span win over Common.
+func Decompact(trace CompactTrace) []*Span {
+	spans := make([]*Span, 0, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if span == nil {
+			continue
+		}
+		span = span.Clone()
+		if len(trace.Common) > 0 && span.Attributes == nil {
+			span.Attributes = make(map[string]any, len(trace.Common))
+		}
+		for key, value := range trace.Common {
+			if _, ok := span.Attributes[key]; !ok {
+				span.Attributes[key] = value
+			}
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
@@ -0,0 +1,76 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func compactTestTrace() []*Span {
+	return []*Span{
+		{TraceID: "t1", SpanID: "a", ServiceName: "api", OperationName: "predict", Attributes: map[string]any{
+			"deployment.env": "prod", "llm.model": "gpt", "http.route": "/predict",
+		}},
+		{TraceID: "t1", SpanID: "b", ParentSpanID: "a", ServiceName: "api", OperationName: "tokenize", Attributes: map[string]any{
+			"deployment.env": "prod", "llm.model": "embed", "tokens": int64(12),
+		}},
+		{TraceID: "t1", SpanID: "c", ParentSpanID: "a", ServiceName: "api", OperationName: "generate", Attributes: map[string]any{
+			"deployment.env": "prod", "tokens": int64(12),
+		}},
+	}
+}
+
+func TestCompact_FactorsOnlyCommonAttributes(t *testing.T) {
+	spans := compactTestTrace()
+
+	compact := Compact(spans)
+
+	want := map[string]any{"deployment.env": "prod"}
+	if !reflect.DeepEqual(compact.Common, want) {
+		t.Fatalf("Common = %v, want %v", compact.Common, want)
+	}
+	for _, span := range compact.Spans {
+		if _, ok := span.Attributes["deployment.env"]; ok {
+			t.Fatalf("span %s still has deployment.env", span.SpanID)
+		}
+	}
+	if got := compact.Spans[1].Attributes["tokens"]; got != int64(12) {
+		t.Fatalf("tokens = %v, want 12 (only shared by two spans)", got)
+	}
+	if _, ok := spans[0].Attributes["deployment.env"]; !ok {
+		t.Fatalf("Compact modified its input")
+	}
+}
+
+func TestCompact_DecompactRoundTrip(t *testing.T) {
+	spans := compactTestTrace()
+
+	restored := Decompact(Compact(spans))
+
+	if len(restored) != len(spans) {
+		t.Fatalf("len(restored) = %d, want %d", len(restored), len(spans))
+	}
+	for i := range spans {
+		if !reflect.DeepEqual(restored[i].Attributes, spans[i].Attributes) {
+			t.Fatalf("span %d attributes = %v, want %v", i, restored[i].Attributes, spans[i].Attributes)
+		}
+		if diffs := DiffSpans(spans[i], restored[i]); len(diffs) != 0 {
+			t.Fatalf("span %d diffs = %v, want none", i, diffs)
+		}
+	}
+}
+
+func TestCompact_NoCommonAttributes(t *testing.T) {
+	spans := []*Span{
+		{SpanID: "a", Attributes: map[string]any{"k": "1"}},
+		{SpanID: "b", Attributes: map[string]any{"k": "2"}},
+	}
+
+	compact := Compact(spans)
+
+	if compact.Common != nil {
+		t.Fatalf("Common = %v, want nil", compact.Common)
+	}
+	if got := compact.Spans[0].Attributes["k"]; got != "1" {
+		t.Fatalf("k = %v, want 1", got)
+	}
+}
@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// virtualNodesPerBackend spreads each backend over the ring so traces divide
+// roughly evenly and a removed backend's share scatters across the rest.
+const virtualNodesPerBackend = 128
+
+var ErrNoBackends = errors.New("consistent hash exporter has no backends")
+
+// ConsistentHashExporter sends every span of a trace to the same backend, chosen
+// by hashing its TraceID onto a ring of named backends. Adding or removing a
+// backend only moves the traces that land on its share of the ring, so
+// downstream tail samplers keep seeing whole traces.
+type ConsistentHashExporter struct {
+	backends map[string]Exporter
+	ring     []ringPoint
+}
+
+type ringPoint struct {
+	hash    uint64
+	backend string
+}
+
+// NewConsistentHashExporter builds the ring from backends, keyed by a stable
+// name such as the replica's address. Names, not map order, decide placement.
+func NewConsistentHashExporter(backends map[string]Exporter) *ConsistentHashExporter {
+	e := &ConsistentHashExporter{
+		backends: backends,
+		ring:     make([]ringPoint, 0, len(backends)*virtualNodesPerBackend),
+	}
+	for name := range backends {
+		for i := range virtualNodesPerBackend {
+			e.ring = append(e.ring, ringPoint{hash: ringHash(name + "#" + strconv.Itoa(i)), backend: name})
+		}
+	}
+	sort.Slice(e.ring, func(i, j int) bool {
+		if e.ring[i].hash != e.ring[j].hash {
+			return e.ring[i].hash < e.ring[j].hash
+		}
+		return e.ring[i].backend < e.ring[j].backend
+	})
+	return e
+}
+
+// Backend returns the name of the backend traceID is assigned to, or "" when
+// there are no backends.
+func (e *ConsistentHashExporter) Backend(traceID string) string {
+	if len(e.ring) == 0 {
+		return ""
+	}
+	h := ringHash(traceID)
+	i := sort.Search(len(e.ring), func(i int) bool { return e.ring[i].hash >= h })
+	if i == len(e.ring) {
+		i = 0
+	}
+	return e.ring[i].backend
+}
+
+// Export calls each backend once with its spans, in name order. Every backend
+// is tried even if one fails; the errors are joined. Exporting no spans is a
+// no-op, even with no backends.
+func (e *ConsistentHashExporter) Export(ctx context.Context, spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	if len(e.ring) == 0 {
+		return ErrNoBackends
+	}
+
+	batches := make(map[string][]*Span)
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		span.mu.Lock()
+		traceID := span.TraceID
+		span.mu.Unlock()
+		name := e.Backend(traceID)
+		batches[name] = append(batches[name], span)
+	}
+
+	names := make([]string, 0, len(batches))
+	for name := range batches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := e.backends[name].Export(ctx, batches[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ringHash is FNV-1a followed by a 64-bit finalizer, since raw FNV clusters
+// keys that differ only in their last characters.
+func ringHash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
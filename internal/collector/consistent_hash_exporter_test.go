@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestConsistentHashExporter_KeepsTraceOnOneBackend(t *testing.T) {
+	backends := map[string]*recordingExporter{"a": {}, "b": {}, "c": {}}
+	exporter := NewConsistentHashExporter(map[string]Exporter{
+		"a": backends["a"], "b": backends["b"], "c": backends["c"],
+	})
+
+	for batch := range 3 {
+		var spans []*Span
+		for trace := range 20 {
+			spans = append(spans, &Span{TraceID: fmt.Sprintf("trace-%d", trace), SpanID: fmt.Sprintf("span-%d", batch)})
+		}
+		if err := exporter.Export(context.Background(), spans); err != nil {
+			t.Fatalf("Export returned error: %v", err)
+		}
+	}
+
+	owner := map[string]string{}
+	for name, backend := range backends {
+		for _, batch := range backend.Batches() {
+			for _, span := range batch {
+				if prev, ok := owner[span.TraceID]; ok && prev != name {
+					t.Fatalf("trace %s went to %s and %s", span.TraceID, prev, name)
+				}
+				owner[span.TraceID] = name
+			}
+		}
+	}
+	if got, want := len(owner), 20; got != want {
+		t.Fatalf("traces exported = %d, want %d", got, want)
+	}
+}
+
+func TestConsistentHashExporter_BalancesTraces(t *testing.T) {
+	noop := ExporterFunc(func(context.Context, []*Span) error { return nil })
+	exporter := NewConsistentHashExporter(map[string]Exporter{"a": noop, "b": noop, "c": noop, "d": noop})
+
+	const traces = 10000
+	counts := map[string]int{}
+	for i := range traces {
+		counts[exporter.Backend(fmt.Sprintf("%032x", i))]++
+	}
+	for name, count := range counts {
+		if count < traces/4*7/10 || count > traces/4*13/10 {
+			t.Fatalf("backend %s got %d of %d traces, want within 30%% of %d", name, count, traces, traces/4)
+		}
+	}
+	if len(counts) != 4 {
+		t.Fatalf("backends used = %v, want all 4", counts)
+	}
+}
+
+func TestConsistentHashExporter_RemovingBackendOnlyMovesItsTraces(t *testing.T) {
+	noop := ExporterFunc(func(context.Context, []*Span) error { return nil })
+	before := NewConsistentHashExporter(map[string]Exporter{"a": noop, "b": noop, "c": noop})
+	after := NewConsistentHashExporter(map[string]Exporter{"a": noop, "b": noop})
+
+	for i := range 1000 {
+		traceID := fmt.Sprintf("trace-%d", i)
+		was, now := before.Backend(traceID), after.Backend(traceID)
+		if was != "c" && was != now {
+			t.Fatalf("trace %s moved from %s to %s, want it to stay", traceID, was, now)
+		}
+	}
+}
+
+func TestConsistentHashExporter_JoinsErrorsAndRequiresBackends(t *testing.T) {
+	if err := NewConsistentHashExporter(nil).Export(context.Background(), nil); err != nil {
+		t.Fatalf("Export of no spans = %v, want nil", err)
+	}
+	if err := NewConsistentHashExporter(nil).Export(context.Background(), []*Span{{TraceID: "t"}}); !errors.Is(err, ErrNoBackends) {
+		t.Fatalf("Export with no backends = %v, want %v", err, ErrNoBackends)
+	}
+
+	boom := errors.New("boom")
+	failing := ExporterFunc(func(context.Context, []*Span) error { return boom })
+	exporter := NewConsistentHashExporter(map[string]Exporter{"a": failing})
+	if err := exporter.Export(context.Background(), []*Span{{TraceID: "t"}}); !errors.Is(err, boom) {
+		t.Fatalf("Export error = %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,17 @@
+package collector
+
+import "context"
+
+type spanContextKey struct{}
+
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+func SpanFromContext(ctx context.Context) (*Span, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	span, ok := ctx.Value(spanContextKey{}).(*Span)
+	return span, ok && span != nil
+}
@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextWithSpan_RoundTrip(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	ctx := ContextWithSpan(context.Background(), span)
+	got, ok := SpanFromContext(ctx)
+	if !ok {
+		t.Fatalf("expected span in context")
+	}
+	if got != span {
+		t.Fatalf("got span %p, want %p", got, span)
+	}
+}
+
+func TestSpanFromContext_Empty(t *testing.T) {
+	if span, ok := SpanFromContext(context.Background()); ok || span != nil {
+		t.Fatalf("expected no span, got %v (ok=%v)", span, ok)
+	}
+
+	ctx := ContextWithSpan(context.Background(), nil)
+	if span, ok := SpanFromContext(ctx); ok || span != nil {
+		t.Fatalf("expected nil span to report ok=false, got %v (ok=%v)", span, ok)
+	}
+}
@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+var csvBaseColumns = []string{
+	"trace_id",
+	"span_id",
+	"parent_span_id",
+	"service_name",
+	"operation_name",
+	"model_name",
+	"start_time_unix_nano",
+	"duration_nanos",
+	"status",
+}
+
+// CSVExporter writes spans as RFC 4180 CSV for spreadsheet analysis: a header
+// row on the first export, then one row per span. attributeKeys become extra
+// columns after the fixed ones; missing attributes are left empty.
+type CSVExporter struct {
+	attributeKeys []string
+
+	mu            sync.Mutex
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func NewCSVExporter(w io.Writer, attributeKeys ...string) *CSVExporter {
+	return &CSVExporter{
+		attributeKeys: attributeKeys,
+		w:             csv.NewWriter(w),
+	}
+}
+
+func (e *CSVExporter) Export(ctx context.Context, spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, span := range spans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if span == nil {
+			continue
+		}
+		if !e.headerWritten {
+			header := append(append([]string{}, csvBaseColumns...), e.attributeKeys...)
+			if err := e.w.Write(header); err != nil {
+				return err
+			}
+			e.headerWritten = true
+		}
+		if err := e.w.Write(e.row(span)); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *CSVExporter) row(span *Span) []string {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	row := make([]string, 0, len(csvBaseColumns)+len(e.attributeKeys))
+	row = append(row,
+		span.TraceID,
+		span.SpanID,
+		span.ParentSpanID,
+		span.ServiceName,
+		span.OperationName,
+		span.ModelName,
+		strconv.FormatInt(span.StartTimeUnixNano, 10),
+		strconv.FormatInt(span.DurationNanos, 10),
+		span.Status.String(),
+	)
+	for _, key := range e.attributeKeys {
+		value, ok := span.Attributes[key]
+		if !ok {
+			row = append(row, "")
+			continue
+		}
+		row = append(row, fmt.Sprint(value))
+	}
+	return row
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestCSVExporter_WritesParsableRows(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewCSVExporter(&buf, AttrLLMPromptTokens, "prompt")
+
+	spans := []*Span{
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "00f067aa0ba902b7",
+			ServiceName:       "inference-api",
+			OperationName:     "predict",
+			ModelName:         "gpt-4o-mini",
+			StartTimeUnixNano: 100,
+			DurationNanos:     42,
+			Status:            StatusOK,
+			Attributes:        map[string]any{AttrLLMPromptTokens: int64(12), "prompt": `say "hi", then stop`},
+		},
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "b7ad6b7169203331",
+			ParentSpanID:      "00f067aa0ba902b7",
+			ServiceName:       "tokenizer",
+			OperationName:     "encode,fast",
+			StartTimeUnixNano: 105,
+			DurationNanos:     3,
+			Status:            StatusError,
+		},
+	}
+	if err := exporter.Export(context.Background(), spans[:1]); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if err := exporter.Export(context.Background(), spans[1:]); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	want := [][]string{
+		{"trace_id", "span_id", "parent_span_id", "service_name", "operation_name", "model_name",
+			"start_time_unix_nano", "duration_nanos", "status", AttrLLMPromptTokens, "prompt"},
+		{"4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", "", "inference-api", "predict", "gpt-4o-mini",
+			"100", "42", "ok", "12", `say "hi", then stop`},
+		{"4bf92f3577b34da6a3ce929d0e0e4736", "b7ad6b7169203331", "00f067aa0ba902b7", "tokenizer", "encode,fast", "",
+			"105", "3", "error", "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("records =\n%q\nwant\n%q", records, want)
+	}
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"container/list"
+	"sync"
+)
+
+const defaultDecisionCacheSize = 10000
+
+// DecisionCache remembers sampling decisions by trace ID, evicting the least
+// recently used trace once capacity is reached.
+type DecisionCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type decisionEntry struct {
+	traceID  string
+	decision SampleDecision
+}
+
+// NewDecisionCache holds at most capacity decisions; capacity <= 0 selects a
+// default of 10000.
+func NewDecisionCache(capacity int) *DecisionCache {
+	if capacity <= 0 {
+		capacity = defaultDecisionCacheSize
+	}
+	return &DecisionCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached decision for traceID and marks it recently used.
+func (c *DecisionCache) Get(traceID string) (SampleDecision, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[traceID]
+	if !ok {
+		return SamplePending, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*decisionEntry).decision, true
+}
+
+// Put records decision for traceID, evicting the least recently used entry if
+// the cache is full. SamplePending is not a decision and is ignored.
+func (c *DecisionCache) Put(traceID string, decision SampleDecision) {
+	if decision == SamplePending {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[traceID]; ok {
+		elem.Value.(*decisionEntry).decision = decision
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[traceID] = c.order.PushFront(&decisionEntry{traceID: traceID, decision: decision})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*decisionEntry).traceID)
+	}
+}
+
+func (c *DecisionCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecisionCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewDecisionCache(2)
+	cache.Put("a", SampleKeep)
+	cache.Put("b", SampleDrop)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatalf("Get(a) missed before eviction")
+	}
+
+	cache.Put("c", SampleKeep)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatalf("Get(b) hit; want b evicted as least recently used")
+	}
+	for _, id := range []string{"a", "c"} {
+		if got, ok := cache.Get(id); !ok || got != SampleKeep {
+			t.Fatalf("Get(%s) = %v, %v; want keep, true", id, got, ok)
+		}
+	}
+	if got := cache.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+}
+
+func TestDecisionCache_IgnoresPending(t *testing.T) {
+	cache := NewDecisionCache(0)
+	cache.Put("a", SamplePending)
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("pending decision was cached")
+	}
+}
+
+// countingSampler records how many times a decision was computed.
+type countingSampler struct {
+	calls int
+}
+
+func (s *countingSampler) ShouldSample(string) bool {
+	s.calls++
+	return false
+}
+
+func TestTailSampler_DecisionCacheReusesDecision(t *testing.T) {
+	inner := &countingSampler{}
+	var late []*Span
+	sampler := NewTailSampler(inner, time.Minute, func(_ string, decision SampleDecision, spans []*Span) {
+		if decision != SampleDrop {
+			t.Fatalf("decision = %v, want drop", decision)
+		}
+		late = spans
+	}, WithDecisionCache(8))
+
+	traceID := GenerateTraceID()
+	sampler.Add(&Span{TraceID: traceID, SpanID: "a"})
+	for range 3 {
+		if got := sampler.Decide(traceID); got != SampleDrop {
+			t.Fatalf("Decide = %v, want drop", got)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("sampler consulted %d times, want 1", inner.calls)
+	}
+
+	sampler.Add(&Span{TraceID: traceID, SpanID: "b", ParentSpanID: "a"})
+	if len(late) != 1 || late[0].SpanID != "b" {
+		t.Fatalf("late span not reported with cached decision: %v", late)
+	}
+	if _, ok := sampler.buffer.GetTrace(traceID); ok {
+		t.Fatalf("late span was buffered for a decided trace")
+	}
+}
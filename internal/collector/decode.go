@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// LineError reports an NDJSON line that could not be decoded as a span.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeStream yields the spans of newline-delimited JSON one at a time, so a
+// dump of any size is processed with memory bounded by its longest line. A
+// malformed line yields a *LineError and decoding continues with the next one;
+// a read error is yielded last. Blank lines are skipped. Spans are not
+// validated.
+func DecodeStream(r io.Reader) iter.Seq2[*Span, error] {
+	return func(yield func(*Span, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for lineNo := 1; scanner.Scan(); lineNo++ {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			span := &Span{}
+			if err := json.Unmarshal(line, span); err != nil {
+				if !yield(nil, &LineError{Line: lineNo, Err: err}) {
+					return
+				}
+				continue
+			}
+			if !yield(span, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
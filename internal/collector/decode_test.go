@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const decodeFixture = `{"trace_id":"t1","span_id":"a","service_name":"api"}
+{"trace_id":"t1","span_id":"b","service_name":"api"}
+{"trace_id":"t1","span_id":
+
+{"trace_id":"t2","span_id":"c","service_name":"api"}
+`
+
+func TestDecodeStream_SkipsBadLines(t *testing.T) {
+	var ids []string
+	var lineErrs []*LineError
+	for span, err := range DecodeStream(strings.NewReader(decodeFixture)) {
+		if err != nil {
+			var lineErr *LineError
+			if !errors.As(err, &lineErr) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lineErrs = append(lineErrs, lineErr)
+			continue
+		}
+		ids = append(ids, span.SpanID)
+	}
+
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Fatalf("span IDs = %s, want a,b,c", got)
+	}
+	if len(lineErrs) != 1 || lineErrs[0].Line != 3 {
+		t.Fatalf("line errors = %v, want one on line 3", lineErrs)
+	}
+}
+
+func TestDecodeStream_StopsEarly(t *testing.T) {
+	count := 0
+	for range DecodeStream(strings.NewReader(decodeFixture)) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Fatalf("count = %d, want 1", count)
+	}
+}
+
+func TestDecodeStream_ReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader(`{"span_id":"a"}`+"\n"), iotest.ErrReader(readErr))
+
+	var spans int
+	var last error
+	for span, err := range DecodeStream(r) {
+		if span != nil {
+			spans++
+		}
+		last = err
+	}
+	if spans != 1 || !errors.Is(last, readErr) {
+		t.Fatalf("spans = %d, last error = %v; want 1, %v", spans, last, readErr)
+	}
+}
@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MissingMode decides what an AttributeDeriver does when its template names an
+// attribute the span does not have.
+type MissingMode int
+
+const (
+	// MissingSkip leaves the span unchanged.
+	MissingSkip MissingMode = iota
+	// MissingEmpty substitutes the empty string and derives the attribute anyway.
+	MissingEmpty
+)
+
+// AttributeDeriver builds a composite attribute, such as "model@version", from
+// a template whose {attr_name} placeholders are filled from the span's own
+// attributes. Non-string values are formatted with fmt.Sprint. A '{' without a
+// closing '}' is kept literally.
+type AttributeDeriver struct {
+	key     string
+	parts   []templatePart
+	missing MissingMode
+}
+
+type templatePart struct {
+	text        string
+	placeholder bool
+}
+
+// DeriveAttribute returns a deriver that sets newKey from template, skipping
+// spans that lack a referenced attribute.
+func DeriveAttribute(newKey, template string) *AttributeDeriver {
+	return &AttributeDeriver{key: newKey, parts: parseTemplate(template)}
+}
+
+// OnMissing sets how spans without a referenced attribute are handled. It
+// returns d for chaining.
+func (d *AttributeDeriver) OnMissing(mode MissingMode) *AttributeDeriver {
+	d.missing = mode
+	return d
+}
+
+// Process sets the derived attribute on span in place. The attribute limits
+// on span still apply.
+func (d *AttributeDeriver) Process(span *Span) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	value, ok := d.render(span.Attributes)
+	span.mu.Unlock()
+	if ok {
+		_ = span.SetAttribute(d.key, value)
+	}
+}
+
+func (d *AttributeDeriver) render(attrs map[string]any) (string, bool) {
+	var b strings.Builder
+	for _, part := range d.parts {
+		if !part.placeholder {
+			b.WriteString(part.text)
+			continue
+		}
+		value, ok := attrs[part.text]
+		if !ok {
+			if d.missing == MissingSkip {
+				return "", false
+			}
+			continue
+		}
+		if s, isString := value.(string); isString {
+			b.WriteString(s)
+		} else {
+			fmt.Fprint(&b, value)
+		}
+	}
+	return b.String(), true
+}
+
+// Wrap returns an Exporter that derives the attribute on every span before
+// handing the batch to next.
+func (d *AttributeDeriver) Wrap(next Exporter) Exporter {
+	return ExporterFunc(func(ctx context.Context, spans []*Span) error {
+		if len(spans) == 0 {
+			return nil
+		}
+		for _, span := range spans {
+			d.Process(span)
+		}
+		return next.Export(ctx, spans)
+	})
+}
+
+func parseTemplate(template string) []templatePart {
+	var parts []templatePart
+	for template != "" {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(template[open:], '}')
+		if end < 0 {
+			break
+		}
+		if open > 0 {
+			parts = append(parts, templatePart{text: template[:open]})
+		}
+		parts = append(parts, templatePart{text: template[open+1 : open+end], placeholder: true})
+		template = template[open+end+1:]
+	}
+	if template != "" {
+		parts = append(parts, templatePart{text: template})
+	}
+	return parts
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDeriveAttribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		mode     MissingMode
+		attrs    map[string]any
+		want     any
+		wantSet  bool
+	}{
+		{
+			name:     "all placeholders present",
+			template: "{llm.model}@{llm.version}",
+			attrs:    map[string]any{"llm.model": "gpt-4o", "llm.version": "2024-08"},
+			want:     "gpt-4o@2024-08",
+			wantSet:  true,
+		},
+		{
+			name:     "non-string value",
+			template: "{llm.model}/{replica}",
+			attrs:    map[string]any{"llm.model": "gpt-4o", "replica": int64(3)},
+			want:     "gpt-4o/3",
+			wantSet:  true,
+		},
+		{
+			name:     "missing placeholder skips",
+			template: "{llm.model}@{llm.version}",
+			mode:     MissingSkip,
+			attrs:    map[string]any{"llm.model": "gpt-4o"},
+			wantSet:  false,
+		},
+		{
+			name:     "missing placeholder inserts empty",
+			template: "{llm.model}@{llm.version}",
+			mode:     MissingEmpty,
+			attrs:    map[string]any{"llm.model": "gpt-4o"},
+			want:     "gpt-4o@",
+			wantSet:  true,
+		},
+		{
+			name:     "unclosed brace is literal",
+			template: "{llm.model}@{version",
+			attrs:    map[string]any{"llm.model": "gpt-4o"},
+			want:     "gpt-4o@{version",
+			wantSet:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := &Span{Attributes: tt.attrs}
+			before := len(tt.attrs)
+
+			DeriveAttribute("llm.label", tt.template).OnMissing(tt.mode).Process(span)
+
+			got, ok := span.GetAttribute("llm.label")
+			if ok != tt.wantSet || got != tt.want {
+				t.Fatalf("llm.label = %v, %v, want %v, %v", got, ok, tt.want, tt.wantSet)
+			}
+			if !tt.wantSet && len(span.Attributes) != before {
+				t.Fatalf("attributes = %v, want span unchanged", span.Attributes)
+			}
+		})
+	}
+}
+
+func TestDeriveAttribute_Wrap(t *testing.T) {
+	exporter := &recordingExporter{}
+	wrapped := DeriveAttribute("llm.label", "{llm.model}@{llm.version}").Wrap(exporter)
+
+	span := &Span{Attributes: map[string]any{"llm.model": "gpt-4o", "llm.version": "1"}}
+	if err := wrapped.Export(context.Background(), []*Span{span}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got, _ := exporter.Batches()[0][0].GetString("llm.label"); got != "gpt-4o@1" {
+		t.Fatalf("exported llm.label = %q, want %q", got, "gpt-4o@1")
+	}
+}
@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+type DiffKind int
+
+const (
+	DiffAdded DiffKind = iota
+	DiffRemoved
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Pseudo-keys used in AttributeDiff for span fields that are not attributes.
+const (
+	DiffKeyStatus   = "status"
+	DiffKeyDuration = "duration_nanos"
+)
+
+// AttributeDiff describes one difference from span a to span b. Before is nil
+// for DiffAdded and After is nil for DiffRemoved.
+type AttributeDiff struct {
+	Key    string
+	Kind   DiffKind
+	Before any
+	After  any
+}
+
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	durationThreshold time.Duration
+}
+
+// WithDurationThreshold ignores duration differences of at most d. By default
+// any difference is reported.
+func WithDurationThreshold(d time.Duration) DiffOption {
+	return func(c *diffConfig) {
+		c.durationThreshold = d
+	}
+}
+
+// DiffSpans compares the attributes, Status and DurationNanos of two spans and
+// returns the differences sorted by key. Identical spans yield an empty slice.
+// A nil span compares as an empty one.
+func DiffSpans(a, b *Span, opts ...DiffOption) []AttributeDiff {
+	var cfg diffConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	a = cloneOrEmpty(a)
+	b = cloneOrEmpty(b)
+
+	var diffs []AttributeDiff
+	for key, before := range a.Attributes {
+		after, ok := b.Attributes[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, AttributeDiff{Key: key, Kind: DiffRemoved, Before: before})
+		case !reflect.DeepEqual(before, after):
+			diffs = append(diffs, AttributeDiff{Key: key, Kind: DiffChanged, Before: before, After: after})
+		}
+	}
+	for key, after := range b.Attributes {
+		if _, ok := a.Attributes[key]; !ok {
+			diffs = append(diffs, AttributeDiff{Key: key, Kind: DiffAdded, After: after})
+		}
+	}
+
+	if a.Status != b.Status {
+		diffs = append(diffs, AttributeDiff{Key: DiffKeyStatus, Kind: DiffChanged, Before: a.Status, After: b.Status})
+	}
+	delta := time.Duration(b.DurationNanos - a.DurationNanos)
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta > cfg.durationThreshold {
+		diffs = append(diffs, AttributeDiff{Key: DiffKeyDuration, Kind: DiffChanged, Before: a.DurationNanos, After: b.DurationNanos})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+func cloneOrEmpty(span *Span) *Span {
+	if span == nil {
+		return &Span{}
+	}
+	return span.Clone()
+}
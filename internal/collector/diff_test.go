@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSpans(t *testing.T) {
+	a := &Span{
+		Status:        StatusOK,
+		DurationNanos: int64(100 * time.Millisecond),
+		Attributes:    map[string]any{"gpu.id": "gpu-0", "cache.hit": true, "tags": []string{"x"}},
+	}
+	b := &Span{
+		Status:        StatusError,
+		DurationNanos: int64(300 * time.Millisecond),
+		Attributes:    map[string]any{"gpu.id": "gpu-1", "retries": int64(2), "tags": []string{"x"}},
+	}
+
+	want := []AttributeDiff{
+		{Key: "cache.hit", Kind: DiffRemoved, Before: true},
+		{Key: DiffKeyDuration, Kind: DiffChanged, Before: int64(100 * time.Millisecond), After: int64(300 * time.Millisecond)},
+		{Key: "gpu.id", Kind: DiffChanged, Before: "gpu-0", After: "gpu-1"},
+		{Key: "retries", Kind: DiffAdded, After: int64(2)},
+		{Key: DiffKeyStatus, Kind: DiffChanged, Before: StatusOK, After: StatusError},
+	}
+	if got := DiffSpans(a, b); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffSpans =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffSpans_DurationThreshold(t *testing.T) {
+	a := &Span{DurationNanos: int64(100 * time.Millisecond)}
+	b := &Span{DurationNanos: int64(110 * time.Millisecond)}
+
+	if got := DiffSpans(a, b, WithDurationThreshold(20*time.Millisecond)); len(got) != 0 {
+		t.Fatalf("DiffSpans within threshold = %+v, want empty", got)
+	}
+	if got := DiffSpans(a, b, WithDurationThreshold(5*time.Millisecond)); len(got) != 1 || got[0].Key != DiffKeyDuration {
+		t.Fatalf("DiffSpans beyond threshold = %+v, want duration diff", got)
+	}
+}
+
+func TestDiffSpans_Identical(t *testing.T) {
+	a := &Span{Status: StatusOK, DurationNanos: 5, Attributes: map[string]any{"k": "v"}}
+	if got := DiffSpans(a, a.Clone()); len(got) != 0 {
+		t.Fatalf("DiffSpans of identical spans = %+v, want empty", got)
+	}
+}
+
+func TestDiffSpans_NilSpan(t *testing.T) {
+	span := &Span{Attributes: map[string]any{"k": "v"}}
+
+	want := []AttributeDiff{{Key: "k", Kind: DiffAdded, After: "v"}}
+	if got := DiffSpans(nil, span); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffSpans(nil, span) = %+v, want %+v", got, want)
+	}
+	want = []AttributeDiff{{Key: "k", Kind: DiffRemoved, Before: "v"}}
+	if got := DiffSpans(span, nil); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffSpans(span, nil) = %+v, want %+v", got, want)
+	}
+	if got := DiffSpans(nil, nil); len(got) != 0 {
+		t.Fatalf("DiffSpans(nil, nil) = %+v, want empty", got)
+	}
+}
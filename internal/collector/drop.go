@@ -0,0 +1,50 @@
+package collector
+
+import "fmt"
+
+// DropReason says why a pipeline stage dropped a span.
+type DropReason int
+
+const (
+	DropQueueFull DropReason = iota
+	DropCollectorClosed
+	DropSampled
+	DropInvalid
+	DropRateLimited
+	// DropCap is a span over SpanStore's per-trace cap.
+	DropCap
+	// DropTooFast is a span under the processor's DropFasterThan threshold.
+	DropTooFast
+)
+
+func (r DropReason) String() string {
+	switch r {
+	case DropQueueFull:
+		return "queue_full"
+	case DropCollectorClosed:
+		return "collector_closed"
+	case DropSampled:
+		return "sampled"
+	case DropInvalid:
+		return "invalid"
+	case DropRateLimited:
+		return "rate_limited"
+	case DropCap:
+		return "cap"
+	case DropTooFast:
+		return "too_fast"
+	default:
+		return fmt.Sprintf("DropReason(%d)", int(r))
+	}
+}
+
+// DropFunc is called for every span a stage drops, on the goroutine that
+// dropped it, so it must not block. span is nil for null entries in a
+// received batch. A nil DropFunc is never called.
+type DropFunc func(span *Span, reason DropReason)
+
+func (f DropFunc) call(span *Span, reason DropReason) {
+	if f != nil {
+		f(span, reason)
+	}
+}
@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDropReason_String(t *testing.T) {
+	tests := map[DropReason]string{
+		DropQueueFull:       "queue_full",
+		DropCollectorClosed: "collector_closed",
+		DropSampled:         "sampled",
+		DropInvalid:         "invalid",
+		DropRateLimited:     "rate_limited",
+		DropCap:             "cap",
+		DropTooFast:         "too_fast",
+		DropReason(42):      "DropReason(42)",
+	}
+	for reason, want := range tests {
+		if got := reason.String(); got != want {
+			t.Fatalf("DropReason(%d).String() = %q, want %q", int(reason), got, want)
+		}
+	}
+}
+
+// dropRecorder collects OnDrop calls keyed by span ID.
+type dropRecorder map[string]DropReason
+
+func (r dropRecorder) record(span *Span, reason DropReason) {
+	id := "<nil>"
+	if span != nil {
+		id = span.SpanID
+	}
+	r[id] = reason
+}
+
+func assertDrops(t *testing.T, got, want dropRecorder) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("drops = %v, want %v", got, want)
+	}
+	for id, reason := range want {
+		if got[id] != reason {
+			t.Fatalf("drop reason for %s = %v, want %v", id, got[id], reason)
+		}
+	}
+}
+
+func TestSpanStore_OnDropReportsCap(t *testing.T) {
+	drops := dropRecorder{}
+	store := NewSpanStore(WithMaxSpansPerTrace(1), WithStoreOnDrop(drops.record))
+
+	store.Add(&Span{TraceID: "t1", SpanID: "a"})
+	store.Add(&Span{TraceID: "t1", SpanID: "a"}) // duplicate, not a drop
+	store.Add(&Span{TraceID: "t1", SpanID: "b"})
+
+	assertDrops(t, drops, dropRecorder{"b": DropCap})
+}
+
+func TestBatchProcessor_OnDropReportsReason(t *testing.T) {
+	drops := dropRecorder{}
+	p := NewBatchProcessor(&recordingExporter{}, 10, time.Hour,
+		DropFasterThan(time.Microsecond), WithProcessorOnDrop(drops.record))
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+	_ = p.Add(&Span{SpanID: "unsampled", DurationNanos: int64(time.Second)})
+	_ = p.Add(&Span{SpanID: "fast", Sampled: true, DurationNanos: 10})
+	_ = p.Add(&Span{SpanID: "kept", Sampled: true, DurationNanos: int64(time.Second)})
+
+	assertDrops(t, drops, dropRecorder{"unsampled": DropSampled, "fast": DropTooFast})
+}
+
+func TestHTTPReceiver_OnDropReportsReason(t *testing.T) {
+	drops := dropRecorder{}
+	handler := NewHTTPReceiver(NewSpanStore(),
+		WithRateLimiter(NewRateLimiter(0, 1)),
+		WithReceiverOnDrop(drops.record)).Handler()
+
+	post := func(body string) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body)))
+	}
+	span := func(spanID, service string) string {
+		return `{"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"` + spanID +
+			`","service_name":"` + service + `","operation_name":"predict","start_time_unix_nano":100}`
+	}
+	post("[" + span("0000000000000001", "api") + "," + span("0000000000000002", "") + "]")
+	post("[" + span("0000000000000003", "api") + "]")
+
+	assertDrops(t, drops, dropRecorder{
+		"0000000000000002": DropInvalid,
+		"0000000000000003": DropRateLimited,
+	})
+}
+
+func TestOTLPTraceServer_OnDropReportsRateLimit(t *testing.T) {
+	drops := dropRecorder{}
+	client := dialOTLPTraceServer(t, NewSpanStore(),
+		WithOTLPRateLimiter(NewRateLimiter(0, 1)),
+		WithOTLPOnDrop(drops.record))
+	ctx := context.Background()
+
+	_, _ = client.Export(ctx, otlpRequestForService(t, "noisy", "00f067aa0ba902b7"))
+	_, _ = client.Export(ctx, otlpRequestForService(t, "noisy", "00f067aa0ba902b8"))
+
+	assertDrops(t, drops, dropRecorder{"00f067aa0ba902b8": DropRateLimited})
+}
@@ -0,0 +1,27 @@
+package collector
+
+import "errors"
+
+// Sentinel errors for span construction and validation. Returned errors wrap
+// them, so callers should test with errors.Is rather than compare strings.
+var (
+	// ErrSpanValidation wraps every error returned by Span.Validate.
+	ErrSpanValidation = errors.New("span validation failed")
+
+	// ErrNilSpan is returned by Span methods called on a nil *Span.
+	ErrNilSpan = errors.New("nil span")
+
+	ErrEmptyTraceID       = errors.New("trace_id is required")
+	ErrInvalidTraceID     = errors.New("invalid trace_id")
+	ErrEmptySpanID        = errors.New("span_id is required")
+	ErrInvalidSpanID      = errors.New("invalid span_id")
+	ErrEmptyServiceName   = errors.New("service_name is required")
+	ErrEmptyOperationName = errors.New("operation_name is required")
+	ErrEmptyModelName     = errors.New("model_name is required")
+	ErrInvalidStartTime   = errors.New("start_time_unix_nano must be > 0")
+	ErrInvalidDuration    = errors.New("invalid duration_nanos")
+	ErrInvalidLink        = errors.New("invalid link")
+
+	// ErrInvalidTraceparent wraps every error returned by ParseTraceparent.
+	ErrInvalidTraceparent = errors.New("invalid traceparent")
+)
@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSpanValidate_Sentinels(t *testing.T) {
+	valid := func() *Span {
+		return &Span{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "00f067aa0ba902b7",
+			StartTimeUnixNano: 1,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Span)
+		want   error
+	}{
+		{name: "empty trace id", mutate: func(s *Span) { s.TraceID = "" }, want: ErrEmptyTraceID},
+		{name: "invalid trace id", mutate: func(s *Span) { s.TraceID = "XYZ" }, want: ErrInvalidTraceID},
+		{name: "empty span id", mutate: func(s *Span) { s.SpanID = "" }, want: ErrEmptySpanID},
+		{name: "zero start", mutate: func(s *Span) { s.StartTimeUnixNano = 0 }, want: ErrInvalidStartTime},
+		{name: "negative duration", mutate: func(s *Span) { s.DurationNanos = -1 }, want: ErrInvalidDuration},
+		{name: "bad link", mutate: func(s *Span) { s.Links = []Link{{}} }, want: ErrInvalidLink},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			span := valid()
+			tc.mutate(span)
+			err := span.Validate()
+			if !errors.Is(err, ErrSpanValidation) {
+				t.Fatalf("error = %v, want it to wrap %v", err, ErrSpanValidation)
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("error = %v, want it to wrap %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewSpan_Sentinels(t *testing.T) {
+	tests := []struct {
+		service, operation, model string
+		want                      error
+	}{
+		{operation: "predict", model: "gpt-4o-mini", want: ErrEmptyServiceName},
+		{service: "api", model: "gpt-4o-mini", want: ErrEmptyOperationName},
+		{service: "api", operation: "predict", want: ErrEmptyModelName},
+	}
+	for _, tt := range tests {
+		if _, err := NewSpan(tt.service, tt.operation, tt.model); !errors.Is(err, tt.want) {
+			t.Fatalf("NewSpan(%q, %q, %q) error = %v, want %v", tt.service, tt.operation, tt.model, err, tt.want)
+		}
+	}
+}
+
+func TestValidateForIngest_Sentinels(t *testing.T) {
+	span := &Span{ServiceName: "api", ModelName: "gpt-4o-mini", StartTimeUnixNano: 1}
+	if err := span.ValidateForIngest(); !errors.Is(err, ErrInvalidDuration) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidDuration)
+	}
+}
+
+func TestNilSpan_MethodsDoNotPanic(t *testing.T) {
+	var span *Span
+
+	if err := span.End(); !errors.Is(err, ErrNilSpan) {
+		t.Fatalf("End error = %v, want %v", err, ErrNilSpan)
+	}
+	if err := span.SetAttribute("k", "v"); !errors.Is(err, ErrNilSpan) {
+		t.Fatalf("SetAttribute error = %v, want %v", err, ErrNilSpan)
+	}
+	if err := span.Validate(); !errors.Is(err, ErrNilSpan) || !errors.Is(err, ErrSpanValidation) {
+		t.Fatalf("Validate error = %v, want %v wrapped in %v", err, ErrNilSpan, ErrSpanValidation)
+	}
+	if err := span.ValidateForIngest(); !errors.Is(err, ErrNilSpan) {
+		t.Fatalf("ValidateForIngest error = %v, want %v", err, ErrNilSpan)
+	}
+	if got, ok := span.GetAttribute("k"); ok || got != nil {
+		t.Fatalf("GetAttribute = %v, %v; want nil, false", got, ok)
+	}
+	if _, ok := span.GetString("k"); ok {
+		t.Fatalf("GetString ok = true on nil span")
+	}
+	if span.Clone() != nil || span.Duration() != 0 || !span.EndTime().IsZero() || span.Ended() {
+		t.Fatalf("nil span accessors returned non-zero values")
+	}
+
+	span.AddEvent("e", nil)
+	span.AddLink("t", "s", nil)
+	span.SetStatus(StatusError, "x")
+	span.RecordError(errors.New("boom"))
+	span.SetKind(SpanKindServer)
+	span.SetModel("gpt-4o-mini")
+	span.SetTokenCounts(1, 2)
+	span.SetDuration(1)
+	span.StartDeadline(1)
+	if span.PropagateStatusTo(&Span{}) {
+		t.Fatalf("PropagateStatusTo from nil span = true")
+	}
+}
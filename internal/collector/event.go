@@ -0,0 +1,42 @@
+package collector
+
+import "maps"
+
+// AttrAnnotationValue is the attribute key Annotate stores its value under.
+const AttrAnnotationValue = "value"
+
+type Event struct {
+	Name         string         `json:"name"`
+	TimeUnixNano int64          `json:"time_unix_nano"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+}
+
+// AddEvent appends a timestamped event to the span. attrs is copied so the
+// caller can keep reusing its map.
+func (s *Span) AddEvent(name string, attrs map[string]any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addEventLocked(name, maps.Clone(attrs))
+}
+
+// Annotate records an event carrying a single "value" attribute, for noting
+// that something happened without building an attribute map.
+func (s *Span) Annotate(name string, value any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addEventLocked(name, map[string]any{AttrAnnotationValue: value})
+}
+
+func (s *Span) addEventLocked(name string, attrs map[string]any) {
+	s.Events = append(s.Events, Event{
+		Name:         name,
+		TimeUnixNano: s.now().UnixNano(),
+		Attributes:   attrs,
+	})
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSpan_LeavesEventsNil(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.Events != nil {
+		t.Fatalf("expected nil events, got %v", span.Events)
+	}
+}
+
+func TestSpanAddEvent_PreservesOrderAndAttributes(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	attrs := map[string]any{"weights.bytes": 1024}
+	span.AddEvent("model loaded", attrs)
+	span.AddEvent("first token emitted", nil)
+	attrs["weights.bytes"] = 0
+
+	if got, want := len(span.Events), 2; got != want {
+		t.Fatalf("event count = %d, want %d", got, want)
+	}
+	if span.Events[0].Name != "model loaded" || span.Events[1].Name != "first token emitted" {
+		t.Fatalf("unexpected event order: %q, %q", span.Events[0].Name, span.Events[1].Name)
+	}
+	if got := span.Events[0].Attributes["weights.bytes"]; got != 1024 {
+		t.Fatalf("weights.bytes = %v, want 1024", got)
+	}
+	if span.Events[0].TimeUnixNano <= 0 || span.Events[1].TimeUnixNano < span.Events[0].TimeUnixNano {
+		t.Fatalf("unexpected timestamps: %d, %d", span.Events[0].TimeUnixNano, span.Events[1].TimeUnixNano)
+	}
+}
+
+func TestSpanAnnotate_AddsSingleValueEvent(t *testing.T) {
+	clock := newFakeClock(time.Unix(100, 0))
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithClock(clock))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	span.AddEvent("model loaded", nil)
+	clock.Advance(time.Second)
+	span.Annotate("cache hit", "kv")
+
+	if got, want := len(span.Events), 2; got != want {
+		t.Fatalf("event count = %d, want %d", got, want)
+	}
+	event := span.Events[1]
+	if event.Name != "cache hit" {
+		t.Fatalf("event name = %q, want %q", event.Name, "cache hit")
+	}
+	if len(event.Attributes) != 1 || event.Attributes["value"] != "kv" {
+		t.Fatalf("event attributes = %v, want only value=kv", event.Attributes)
+	}
+	if got, want := event.TimeUnixNano, time.Unix(101, 0).UnixNano(); got != want {
+		t.Fatalf("event time = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// Exporter sends a batch of spans somewhere. Implementations should give up and
+// return ctx.Err() once ctx is done rather than block on a slow downstream.
+type Exporter interface {
+	Export(ctx context.Context, spans []*Span) error
+}
+
+// ExporterFunc adapts a plain function to the Exporter interface.
+type ExporterFunc func(ctx context.Context, spans []*Span) error
+
+func (f ExporterFunc) Export(ctx context.Context, spans []*Span) error {
+	return f(ctx, spans)
+}
+
+// StdoutExporter writes one JSON object per line. Despite the name it accepts any
+// io.Writer so tests and tools can capture the output.
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &StdoutExporter{w: w}
+}
+
+func (e *StdoutExporter) Export(ctx context.Context, spans []*Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	enc := json.NewEncoder(e.w)
+	for _, span := range spans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if span == nil {
+			continue
+		}
+		if err := enc.Encode(span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
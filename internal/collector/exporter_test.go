@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestStdoutExporter_WritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewStdoutExporter(&buf)
+
+	spans := []*Span{
+		{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "a", ServiceName: "inference-api", StartTimeUnixNano: 1, DurationNanos: 10, Status: StatusOK},
+		{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "b", ParentSpanID: "a", ServiceName: "tokenizer", StartTimeUnixNano: 2, DurationNanos: 3},
+	}
+	if err := exporter.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+
+	if got, want := len(lines), 2; got != want {
+		t.Fatalf("line count = %d, want %d", got, want)
+	}
+	if lines[0]["span_id"] != "a" || lines[0]["status"] != "ok" {
+		t.Fatalf("unexpected first line: %v", lines[0])
+	}
+	if lines[1]["span_id"] != "b" || lines[1]["parent_span_id"] != "a" {
+		t.Fatalf("unexpected second line: %v", lines[1])
+	}
+}
+
+func TestExporters_NilAndEmptyInput(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("exporter sent a request for empty input")
+	}))
+	defer srv.Close()
+
+	fileExporter, err := NewFileExporter(filepath.Join(t.TempDir(), "spans.ndjson"), 0)
+	if err != nil {
+		t.Fatalf("NewFileExporter: %v", err)
+	}
+	defer fileExporter.Close()
+
+	var out bytes.Buffer
+	exporters := map[string]Exporter{
+		"stdout":   NewStdoutExporter(&out),
+		"zipkin":   NewZipkinExporter(srv.URL, srv.Client()),
+		"file":     fileExporter,
+		"csv":      NewCSVExporter(&out),
+		"multi":    NewMultiExporter(NewStdoutExporter(&out)),
+		"metrics":  NewMetricsAggregator(nil),
+		"redactor": NewRedactor(RedactRemove, "k").Wrap(NewStdoutExporter(&out)),
+	}
+	for name, exporter := range exporters {
+		for _, spans := range [][]*Span{nil, {}, {nil}} {
+			if err := exporter.Export(context.Background(), spans); err != nil {
+				t.Fatalf("%s: Export(%v) returned error: %v", name, spans, err)
+			}
+		}
+	}
+	if out.Len() != 0 {
+		t.Fatalf("exporters wrote %q for empty input", out.String())
+	}
+}
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileExporter appends spans to path as newline-delimited JSON. When a write
+// would push the file past maxBytes it rotates: path becomes path.1, the old
+// path.1 becomes path.2, and so on.
+type FileExporter struct {
+	path     string
+	maxBytes int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func NewFileExporter(path string, maxBytes int64) (*FileExporter, error) {
+	e := &FileExporter{
+		path:     path,
+		maxBytes: maxBytes,
+	}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *FileExporter) Export(ctx context.Context, spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file == nil {
+		return fmt.Errorf("file exporter for %s is closed", e.path)
+	}
+
+	for _, span := range spans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if span == nil {
+			continue
+		}
+		line, err := json.Marshal(span)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+
+		if e.maxBytes > 0 && e.size > 0 && e.size+int64(len(line)) > e.maxBytes {
+			if err := e.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := e.file.Write(line)
+		e.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *FileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file = nil
+	return err
+}
+
+func (e *FileExporter) open() error {
+	file, err := os.OpenFile(e.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	e.file = file
+	e.size = info.Size()
+	return nil
+}
+
+func (e *FileExporter) rotate() error {
+	if err := e.file.Close(); err != nil {
+		return err
+	}
+	e.file = nil
+
+	highest := 0
+	for {
+		if _, err := os.Stat(e.backupPath(highest + 1)); err != nil {
+			break
+		}
+		highest++
+	}
+	for i := highest; i >= 1; i-- {
+		if err := os.Rename(e.backupPath(i), e.backupPath(i+1)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(e.path, e.backupPath(1)); err != nil {
+		return err
+	}
+	return e.open()
+}
+
+func (e *FileExporter) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", e.path, i)
+}
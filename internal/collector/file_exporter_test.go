@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readJSONLines(t *testing.T, path string) []*Span {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var spans []*Span
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		span := &Span{}
+		if err := json.Unmarshal(scanner.Bytes(), span); err != nil {
+			t.Fatalf("%s: invalid JSON line %q: %v", path, scanner.Text(), err)
+		}
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func TestFileExporter_RotatesWhenLimitExceeded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.ndjson")
+
+	line, err := json.Marshal(&Span{SpanID: "a"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	// Room for three lines per file.
+	exporter, err := NewFileExporter(path, int64(3*(len(line)+1)))
+	if err != nil {
+		t.Fatalf("NewFileExporter: %v", err)
+	}
+	t.Cleanup(func() { _ = exporter.Close() })
+
+	if err := exporter.Export(context.Background(), []*Span{{SpanID: "a"}, {SpanID: "b"}, {SpanID: "c"}}); err != nil {
+		t.Fatalf("first Export: %v", err)
+	}
+	if err := exporter.Export(context.Background(), []*Span{{SpanID: "d"}, {SpanID: "e"}}); err != nil {
+		t.Fatalf("second Export: %v", err)
+	}
+
+	rotated := readJSONLines(t, path+".1")
+	current := readJSONLines(t, path)
+	if len(rotated) != 3 || rotated[0].SpanID != "a" || rotated[2].SpanID != "c" {
+		t.Fatalf("unexpected rotated file contents: %+v", rotated)
+	}
+	if len(current) != 2 || current[0].SpanID != "d" || current[1].SpanID != "e" {
+		t.Fatalf("unexpected current file contents: %+v", current)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Fatalf("expected no second rotation, stat err = %v", err)
+	}
+}
+
+func TestFileExporter_ShiftsExistingBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.ndjson")
+	exporter, err := NewFileExporter(path, 1)
+	if err != nil {
+		t.Fatalf("NewFileExporter: %v", err)
+	}
+	t.Cleanup(func() { _ = exporter.Close() })
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := exporter.Export(context.Background(), []*Span{{SpanID: id}}); err != nil {
+			t.Fatalf("Export %s: %v", id, err)
+		}
+	}
+
+	for file, want := range map[string]string{path: "c", path + ".1": "b", path + ".2": "a"} {
+		spans := readJSONLines(t, file)
+		if len(spans) != 1 || spans[0].SpanID != want {
+			t.Fatalf("%s = %+v, want single span %q", file, spans, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AttrRPCMethod         = "rpc.method"
+	AttrRPCGRPCStatusCode = "rpc.grpc.status_code"
+)
+
+// UnaryServerInterceptor is the gRPC counterpart of Middleware. It continues the
+// trace from an incoming "traceparent" metadata entry, or starts a new one, and
+// runs the handler with the server span in its context. It accepts the same
+// options as Middleware.
+func UnaryServerInterceptor(opts ...MiddlewareOption) grpc.UnaryServerInterceptor {
+	m := &middleware{serviceName: defaultMiddlewareService}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		span := newSpan(m.serviceName, info.FullMethod, "")
+		span.Kind = SpanKindServer
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("traceparent"); len(values) > 0 {
+				_ = span.continueTrace(values[0])
+			}
+		}
+		applySpanOptions(span, nil)
+		span.Attributes[AttrRPCMethod] = info.FullMethod
+
+		resp, err := handler(ContextWithSpan(ctx, span), req)
+
+		st := status.Convert(err)
+		span.SetAttribute(AttrRPCGRPCStatusCode, int64(st.Code()))
+		if isServerErrorCode(st.Code()) {
+			span.SetStatus(StatusError, st.Message())
+		}
+		_ = span.End()
+		if m.onEnd != nil {
+			m.onEnd(span)
+		}
+		return resp, err
+	}
+}
+
+// isServerErrorCode reports whether code points at the server rather than the
+// caller, mirroring the 5xx rule in Middleware.
+func isServerErrorCode(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type failingTraceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	err error
+	got *Span
+}
+
+func (s *failingTraceServer) Export(ctx context.Context, _ *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	s.got, _ = SpanFromContext(ctx)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func dialIntercepted(t *testing.T, srv coltracepb.TraceServiceServer, onEnd func(*Span)) coltracepb.TraceServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(
+		UnaryServerInterceptor(WithServiceName("collector"), WithSpanHandler(onEnd)),
+	))
+	coltracepb.RegisterTraceServiceServer(grpcServer, srv)
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return coltracepb.NewTraceServiceClient(conn)
+}
+
+func TestUnaryServerInterceptor_ContinuesTraceAndMapsStatus(t *testing.T) {
+	srv := &failingTraceServer{err: status.Error(codes.Internal, "exporter crashed")}
+	var ended *Span
+	client := dialIntercepted(t, srv, func(span *Span) { ended = span })
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if _, err := client.Export(ctx, &coltracepb.ExportTraceServiceRequest{}); status.Code(err) != codes.Internal {
+		t.Fatalf("Export error = %v, want Internal", err)
+	}
+
+	if ended == nil || ended != srv.got {
+		t.Fatalf("handler span %p and ended span %p differ", srv.got, ended)
+	}
+	if ended.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || ended.ParentSpanID != "00f067aa0ba902b7" {
+		t.Fatalf("ids = %s parent %s", ended.TraceID, ended.ParentSpanID)
+	}
+	if ended.Kind != SpanKindServer {
+		t.Fatalf("Kind = %v, want %v", ended.Kind, SpanKindServer)
+	}
+	if ended.Status != StatusError || ended.StatusDescription != "exporter crashed" {
+		t.Fatalf("Status = %v %q, want error", ended.Status, ended.StatusDescription)
+	}
+	if ended.OperationName != "/opentelemetry.proto.collector.trace.v1.TraceService/Export" {
+		t.Fatalf("OperationName = %q", ended.OperationName)
+	}
+}
+
+func TestUnaryServerInterceptor_NoMetadataStartsRootTrace(t *testing.T) {
+	srv := &failingTraceServer{err: status.Error(codes.InvalidArgument, "bad request")}
+	var ended *Span
+	client := dialIntercepted(t, srv, func(span *Span) { ended = span })
+
+	_, _ = client.Export(context.Background(), &coltracepb.ExportTraceServiceRequest{})
+
+	if ended == nil {
+		t.Fatalf("span handler was not called")
+	}
+	if ended.ParentSpanID != "" || len(ended.TraceID) != 32 {
+		t.Fatalf("expected fresh root trace, got trace=%q parent=%q", ended.TraceID, ended.ParentSpanID)
+	}
+	if ended.Status != StatusUnset {
+		t.Fatalf("client error Status = %v, want %v", ended.Status, StatusUnset)
+	}
+	if got, _ := ended.GetInt64(AttrRPCGRPCStatusCode); got != int64(codes.InvalidArgument) {
+		t.Fatalf("%s = %d, want %d", AttrRPCGRPCStatusCode, got, codes.InvalidArgument)
+	}
+}
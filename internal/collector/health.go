@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Health serves Kubernetes-style probes. /healthz answers 200 whenever the
+// process is serving; /readyz answers 200 only between MarkReady and
+// MarkShuttingDown, so traffic is not routed to a collector whose pipeline is
+// not wired up yet or is draining.
+type Health struct {
+	ready        atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// MarkReady signals that the export pipeline is initialized.
+func (h *Health) MarkReady() {
+	if !h.shuttingDown.Load() {
+		h.ready.Store(true)
+	}
+}
+
+// MarkShuttingDown makes /readyz fail for the rest of the process lifetime.
+func (h *Health) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+	h.ready.Store(false)
+}
+
+func (h *Health) Ready() bool {
+	return h.ready.Load()
+}
+
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeProbe(w, http.StatusOK, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		switch {
+		case h.shuttingDown.Load():
+			writeProbe(w, http.StatusServiceUnavailable, "shutting down")
+		case !h.ready.Load():
+			writeProbe(w, http.StatusServiceUnavailable, "not ready")
+		default:
+			writeProbe(w, http.StatusOK, "ready")
+		}
+	})
+	return mux
+}
+
+func writeProbe(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body + "\n"))
+}
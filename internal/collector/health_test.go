@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func probe(t *testing.T, h http.Handler, path string) int {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+	return rr.Code
+}
+
+func TestHealth_ProbeStates(t *testing.T) {
+	health := NewHealth()
+	handler := health.Handler()
+
+	tests := []struct {
+		name        string
+		transition  func()
+		wantHealthz int
+		wantReadyz  int
+	}{
+		{name: "not ready", transition: func() {}, wantHealthz: http.StatusOK, wantReadyz: http.StatusServiceUnavailable},
+		{name: "ready", transition: health.MarkReady, wantHealthz: http.StatusOK, wantReadyz: http.StatusOK},
+		{name: "shutting down", transition: health.MarkShuttingDown, wantHealthz: http.StatusOK, wantReadyz: http.StatusServiceUnavailable},
+		{name: "ready after shutdown is ignored", transition: health.MarkReady, wantHealthz: http.StatusOK, wantReadyz: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		tt.transition()
+		if got := probe(t, handler, "/healthz"); got != tt.wantHealthz {
+			t.Fatalf("%s: /healthz = %d, want %d", tt.name, got, tt.wantHealthz)
+		}
+		if got := probe(t, handler, "/readyz"); got != tt.wantReadyz {
+			t.Fatalf("%s: /readyz = %d, want %d", tt.name, got, tt.wantReadyz)
+		}
+	}
+}
+
+func TestHealth_MethodNotAllowed(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NewHealth().Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST /healthz = %d, want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+}
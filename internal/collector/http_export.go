@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// HTTPExportOption configures the shared HTTP transport used by the HTTP-based
+// exporters.
+type HTTPExportOption func(*httpPoster)
+
+// WithGzip compresses request bodies and sets Content-Encoding: gzip.
+func WithGzip() HTTPExportOption {
+	return func(p *httpPoster) {
+		p.gzip = true
+	}
+}
+
+const (
+	minRetryBackoff     = time.Millisecond
+	defaultRetryBackoff = 30 * time.Second
+)
+
+// WithRetry retries a failed export up to maxAttempts times in total, waiting
+// an exponentially growing, jittered backoff between attempts. The backoff
+// starts at initial, at least 1ms, and is capped at maxBackoff, or at 30s when
+// maxBackoff is not positive. Only connection errors and 5xx responses are
+// retried; a 4xx means the payload itself is bad. Retries stop once the Export
+// context is done or its deadline would pass before the next attempt.
+func WithRetry(maxAttempts int, initial, maxBackoff time.Duration) HTTPExportOption {
+	initial = max(initial, minRetryBackoff)
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryBackoff
+	}
+	maxBackoff = max(maxBackoff, initial)
+	return func(p *httpPoster) {
+		p.maxAttempts = maxAttempts
+		p.initialBackoff = initial
+		p.maxBackoff = maxBackoff
+	}
+}
+
+type httpPoster struct {
+	name     string
+	endpoint string
+	client   *http.Client
+	gzip     bool
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func newHTTPPoster(name, endpoint string, client *http.Client, opts []HTTPExportOption) *httpPoster {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	p := &httpPoster{
+		name:     name,
+		endpoint: endpoint,
+		client:   client,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *httpPoster) postJSON(ctx context.Context, body []byte) error {
+	if p.gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	backoff := p.initialBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := p.post(ctx, body)
+		if err == nil || !retryable || attempt >= p.maxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if backoff > p.maxBackoff/2 {
+			backoff = p.maxBackoff
+		} else {
+			backoff *= 2
+		}
+	}
+}
+
+// post makes one attempt and reports whether a failure is worth retrying.
+func (p *httpPoster) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode >= 500, fmt.Errorf("%s export failed: %s", p.name, resp.Status)
+	}
+	return false, nil
+}
@@ -0,0 +1,234 @@
+package collector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func postToReceiver(t *testing.T, body []byte, gzipped bool) *SpanStore {
+	t.Helper()
+
+	spanStore := NewSpanStore()
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", bytes.NewReader(body))
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	rr := httptest.NewRecorder()
+	NewHTTPReceiver(spanStore).Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d (body %q)", rr.Code, http.StatusAccepted, rr.Body.String())
+	}
+	return spanStore
+}
+
+func TestHTTPReceiver_GzipMatchesUncompressed(t *testing.T) {
+	plain := postToReceiver(t, []byte(receiverTestBody), false)
+	gzipped := postToReceiver(t, gzipBytes(t, []byte(receiverTestBody)), true)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	plainSpans, _ := plain.GetTrace(traceID)
+	gzipSpans, _ := gzipped.GetTrace(traceID)
+	if len(gzipSpans) != 2 {
+		t.Fatalf("gzip path stored %d spans, want 2", len(gzipSpans))
+	}
+	if !reflect.DeepEqual(plainSpans, gzipSpans) {
+		t.Fatalf("gzip spans differ from plain spans")
+	}
+}
+
+func TestHTTPReceiver_InvalidGzip(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	NewHTTPReceiver(NewSpanStore()).Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHTTPReceiver_GzipBombTooLarge(t *testing.T) {
+	const limit = 4096
+	payload := []byte(strings.Repeat(" ", 1<<20) + receiverTestBody)
+	compressed := gzipBytes(t, payload)
+	if len(compressed) >= limit {
+		t.Fatalf("compressed size = %d, want under %d", len(compressed), limit)
+	}
+
+	spanStore := NewSpanStore()
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	NewHTTPReceiver(spanStore, WithMaxBodyBytes(limit)).Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+	if _, ok := spanStore.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736"); ok {
+		t.Fatalf("oversized body stored spans")
+	}
+}
+
+func TestHTTPReceiver_BodyTooLarge(t *testing.T) {
+	body := strings.Repeat(" ", 8192) + receiverTestBody
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	NewHTTPReceiver(NewSpanStore(), WithMaxBodyBytes(4096)).Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestZipkinExporter_WithGzip(t *testing.T) {
+	var encoding string
+	var decoded []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("server gzip reader: %v", err)
+			return
+		}
+		raw, _ := io.ReadAll(zr)
+		_ = json.Unmarshal(raw, &decoded)
+	}))
+	defer srv.Close()
+
+	exporter := NewZipkinExporter(srv.URL, srv.Client(), WithGzip())
+	if err := exporter.Export(context.Background(), []*Span{{SpanID: "a", OperationName: "predict"}}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if encoding != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", encoding)
+	}
+	if len(decoded) != 1 || decoded[0]["name"] != "predict" {
+		t.Fatalf("decoded body = %v", decoded)
+	}
+}
+
+func TestZipkinExporter_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	exporter := NewZipkinExporter(server.URL, server.Client(), WithRetry(5, time.Millisecond, 4*time.Millisecond))
+	span := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", ServiceName: "api"}
+	if err := exporter.Export(context.Background(), []*Span{span}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("server calls = %d, want 3", got)
+	}
+}
+
+func TestZipkinExporter_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter := NewZipkinExporter(server.URL, server.Client(), WithRetry(5, time.Millisecond, time.Millisecond))
+	err := exporter.Export(context.Background(), []*Span{{TraceID: "t", SpanID: "s"}})
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("Export error = %v, want 400 failure", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("server calls = %d, want 1", got)
+	}
+}
+
+func TestZipkinExporter_RetryStopsAtDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	exporter := NewZipkinExporter(server.URL, server.Client(), WithRetry(10, time.Second, time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := exporter.Export(ctx, []*Span{{TraceID: "t", SpanID: "s"}}); err == nil {
+		t.Fatalf("Export returned nil, want the server error")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("Export took %v; a backoff past the deadline should not be waited out", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("server calls = %d, want 1", got)
+	}
+}
+
+func TestWithRetry_ClampsBackoff(t *testing.T) {
+	tests := []struct {
+		name                string
+		initial, maxBackoff time.Duration
+		wantInit, wantMax   time.Duration
+	}{
+		{name: "negative initial", initial: -time.Second, maxBackoff: time.Second, wantInit: minRetryBackoff, wantMax: time.Second},
+		{name: "zero initial", initial: 0, maxBackoff: time.Second, wantInit: minRetryBackoff, wantMax: time.Second},
+		{name: "no cap", initial: time.Second, maxBackoff: 0, wantInit: time.Second, wantMax: defaultRetryBackoff},
+		{name: "cap below initial", initial: time.Second, maxBackoff: time.Millisecond, wantInit: time.Second, wantMax: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newHTTPPoster("zipkin", "http://localhost", nil, []HTTPExportOption{WithRetry(3, tt.initial, tt.maxBackoff)})
+			if p.initialBackoff != tt.wantInit || p.maxBackoff != tt.wantMax {
+				t.Fatalf("backoff = %v..%v, want %v..%v", p.initialBackoff, p.maxBackoff, tt.wantInit, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestZipkinExporter_RetryWithNegativeInitial(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	exporter := NewZipkinExporter(server.URL, server.Client(), WithRetry(3, -time.Second, 0))
+	if err := exporter.Export(context.Background(), []*Span{{TraceID: "t", SpanID: "s"}}); err == nil {
+		t.Fatalf("Export returned nil, want the server error")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("server calls = %d, want 3", got)
+	}
+}
@@ -0,0 +1,262 @@
+package collector
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+var ErrReceiverClosed = errors.New("receiver is shut down")
+
+// defaultMaxBodyBytes bounds a request body, both as sent and after gzip
+// decompression.
+const defaultMaxBodyBytes = 16 << 20
+
+type HTTPReceiver struct {
+	store     *SpanStore
+	processor *BatchProcessor
+	limiter   *RateLimiter
+	keys      *KeyNormalizer
+	onDrop    DropFunc
+	maxBody   int64
+
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+}
+
+type ReceiverOption func(*HTTPReceiver)
+
+// WithProcessor forwards every accepted span to processor in addition to the
+// store. Shutdown flushes it.
+func WithProcessor(processor *BatchProcessor) ReceiverOption {
+	return func(r *HTTPReceiver) {
+		r.processor = processor
+	}
+}
+
+// WithRateLimiter rejects spans from services over their rate. Spans within
+// their service's rate are still stored; the response is 429 only when the
+// limiter rejected spans and none were accepted. Retrying a partly accepted
+// request is safe: spans already stored are accepted as duplicates without
+// taking a token, so the retry's budget goes to the spans still missing.
+func WithRateLimiter(limiter *RateLimiter) ReceiverOption {
+	return func(r *HTTPReceiver) {
+		r.limiter = limiter
+	}
+}
+
+// WithMaxBodyBytes caps request bodies at n bytes, both as sent and after gzip
+// decompression, answering 413 past it. n <= 0 keeps the 16 MiB default.
+func WithMaxBodyBytes(n int64) ReceiverOption {
+	return func(r *HTTPReceiver) {
+		if n > 0 {
+			r.maxBody = n
+		}
+	}
+}
+
+// WithKeyNormalizer rewrites attribute keys of accepted spans before they are
+// stored.
+func WithKeyNormalizer(normalizer *KeyNormalizer) ReceiverOption {
+	return func(r *HTTPReceiver) {
+		r.keys = normalizer
+	}
+}
+
+// WithReceiverOnDrop calls fn for every span the receiver rejects, with
+// DropInvalid or DropRateLimited.
+func WithReceiverOnDrop(fn DropFunc) ReceiverOption {
+	return func(r *HTTPReceiver) {
+		r.onDrop = fn
+	}
+}
+
+// receiveResponse counts rate-limited spans in RejectedCount and again in
+// RateLimitedCount.
+type receiveResponse struct {
+	AcceptedCount    int `json:"accepted_count"`
+	RejectedCount    int `json:"rejected_count"`
+	RateLimitedCount int `json:"rate_limited_count,omitempty"`
+}
+
+// dryRunResponse reports validation results without storing anything. Errors
+// lists only the invalid spans; Valid is true when there are none.
+type dryRunResponse struct {
+	Valid        bool              `json:"valid"`
+	ValidCount   int               `json:"valid_count"`
+	InvalidCount int               `json:"invalid_count"`
+	Errors       []spanCheckResult `json:"errors"`
+}
+
+type spanCheckResult struct {
+	Index  int      `json:"index"`
+	SpanID string   `json:"span_id,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+// isDryRun reports whether the client asked for validation only, through an
+// X-Dry-Run: true header or a dry_run=true query parameter.
+func isDryRun(req *http.Request) bool {
+	return req.Header.Get("X-Dry-Run") == "true" || req.URL.Query().Get("dry_run") == "true"
+}
+
+func NewHTTPReceiver(store *SpanStore, opts ...ReceiverOption) *HTTPReceiver {
+	if store == nil {
+		store = NewSpanStore()
+	}
+	r := &HTTPReceiver{
+		store:   store,
+		maxBody: defaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *HTTPReceiver) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/spans", r.handleSpans)
+	return mux
+}
+
+func (r *HTTPReceiver) handleSpans(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		http.Error(w, ErrReceiverClosed.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	r.inflight.Add(1)
+	r.mu.Unlock()
+	defer r.inflight.Done()
+
+	var body io.Reader = http.MaxBytesReader(w, req.Body, r.maxBody)
+	// decompressed stays nil for plain bodies; its N hitting 0 means the
+	// decompressed stream went past maxBody.
+	var decompressed *io.LimitedReader
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			if isBodyTooLarge(err) {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "invalid gzip body", http.StatusBadRequest)
+			return
+		}
+		defer zr.Close()
+		decompressed = &io.LimitedReader{R: zr, N: r.maxBody + 1}
+		body = decompressed
+	}
+
+	var spans []*Span
+	err := json.NewDecoder(body).Decode(&spans)
+	if isBodyTooLarge(err) || (decompressed != nil && decompressed.N == 0) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if isDryRun(req) {
+		writeDryRun(w, spans)
+		return
+	}
+
+	var resp receiveResponse
+	for _, span := range spans {
+		if span == nil || span.Validate() != nil {
+			resp.RejectedCount++
+			r.onDrop.call(span, DropInvalid)
+			continue
+		}
+		if !r.store.Has(span.TraceID, span.SpanID) && !r.limiter.Allow(span.ServiceName) {
+			resp.RejectedCount++
+			resp.RateLimitedCount++
+			r.onDrop.call(span, DropRateLimited)
+			continue
+		}
+		if r.keys != nil {
+			r.keys.Process(span)
+		}
+		if r.store.Add(span) && r.processor != nil {
+			_ = r.processor.Add(span)
+		}
+		resp.AcceptedCount++
+	}
+
+	status := http.StatusAccepted
+	if resp.RateLimitedCount > 0 && resp.AcceptedCount == 0 {
+		status = http.StatusTooManyRequests
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func isBodyTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+func writeDryRun(w http.ResponseWriter, spans []*Span) {
+	resp := dryRunResponse{Errors: []spanCheckResult{}}
+	for i, span := range spans {
+		errs := span.validationErrors()
+		if len(errs) == 0 {
+			resp.ValidCount++
+			continue
+		}
+		result := spanCheckResult{Index: i}
+		if span != nil {
+			result.SpanID = span.SpanID
+		}
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		resp.Errors = append(resp.Errors, result)
+		resp.InvalidCount++
+	}
+	resp.Valid = resp.InvalidCount == 0
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Shutdown rejects new requests with 503, waits for in-flight requests to finish
+// and then flushes the processor. It returns ctx.Err() if ctx expires first.
+func (r *HTTPReceiver) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		r.inflight.Wait()
+		if r.processor != nil {
+			done <- r.processor.Shutdown(ctx)
+			return
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,316 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const receiverTestBody = `[
+	{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id": "00f067aa0ba902b7",
+		"service_name": "inference-api",
+		"operation_name": "predict",
+		"model_name": "gpt-4o-mini",
+		"start_time_unix_nano": 100,
+		"duration_nanos": 10,
+		"status": "ok"
+	},
+	{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id": "b7ad6b7169203331",
+		"parent_span_id": "00f067aa0ba902b7",
+		"service_name": "tokenizer",
+		"operation_name": "encode",
+		"model_name": "gpt-4o-mini",
+		"start_time_unix_nano": 105,
+		"duration_nanos": 3,
+		"status": "ok"
+	}
+]`
+
+func TestHTTPReceiver_StoresPostedSpans(t *testing.T) {
+	spanStore := NewSpanStore()
+	receiver := NewHTTPReceiver(spanStore)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(receiverTestBody))
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusAccepted)
+	}
+
+	var resp receiveResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.AcceptedCount != 2 || resp.RejectedCount != 0 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	spans, ok := spanStore.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736")
+	if !ok || len(spans) != 2 {
+		t.Fatalf("stored spans = %d (ok=%v), want 2", len(spans), ok)
+	}
+	if spans[1].ParentSpanID != "00f067aa0ba902b7" || spans[1].Status != StatusOK {
+		t.Fatalf("unexpected stored child span: %+v", spans[1])
+	}
+}
+
+func TestHTTPReceiver_RejectsInvalidSpans(t *testing.T) {
+	spanStore := NewSpanStore()
+	receiver := NewHTTPReceiver(spanStore)
+
+	body := `[{"trace_id": "not-hex", "span_id": "a", "start_time_unix_nano": 1}]`
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	var resp receiveResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.AcceptedCount != 0 || resp.RejectedCount != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if _, ok := spanStore.GetTrace("not-hex"); ok {
+		t.Fatalf("expected invalid span to not be stored")
+	}
+}
+
+func TestHTTPReceiver_MalformedJSON(t *testing.T) {
+	receiver := NewHTTPReceiver(NewSpanStore())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(`[{"trace_id":`))
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHTTPReceiver_MethodNotAllowed(t *testing.T) {
+	receiver := NewHTTPReceiver(NewSpanStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/spans", nil)
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHTTPReceiver_ShutdownFlushesProcessor(t *testing.T) {
+	exporter := &recordingExporter{}
+	processor := NewBatchProcessor(exporter, 100, time.Hour)
+	receiver := NewHTTPReceiver(NewSpanStore(), WithProcessor(processor))
+	srv := httptest.NewServer(receiver.Handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/spans", "application/json", strings.NewReader(receiverTestBody))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	if got := exporter.SpanCount(); got != 0 {
+		t.Fatalf("exported %d spans before shutdown, want 0", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := receiver.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	if got, want := exporter.SpanCount(), 2; got != want {
+		t.Fatalf("exported = %d, want %d", got, want)
+	}
+
+	resp, err = http.Post(srv.URL+"/v1/spans", "application/json", strings.NewReader(receiverTestBody))
+	if err != nil {
+		t.Fatalf("post after shutdown: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status after shutdown = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+func TestHTTPReceiver_RateLimitPerService(t *testing.T) {
+	spanStore := NewSpanStore()
+	handler := NewHTTPReceiver(spanStore, WithRateLimiter(NewRateLimiter(0, 2))).Handler()
+
+	post := func(service, spanID string) int {
+		body := `[{"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"` + spanID +
+			`","service_name":"` + service + `","operation_name":"predict","start_time_unix_nano":100}]`
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body)))
+		return rr.Code
+	}
+
+	for _, spanID := range []string{"0000000000000001", "0000000000000002"} {
+		if code := post("noisy", spanID); code != http.StatusAccepted {
+			t.Fatalf("noisy span %s status = %d, want %d", spanID, code, http.StatusAccepted)
+		}
+	}
+	if code := post("noisy", "0000000000000003"); code != http.StatusTooManyRequests {
+		t.Fatalf("over-limit status = %d, want %d", code, http.StatusTooManyRequests)
+	}
+	if code := post("quiet", "0000000000000004"); code != http.StatusAccepted {
+		t.Fatalf("quiet status = %d, want %d", code, http.StatusAccepted)
+	}
+
+	spans, _ := spanStore.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736")
+	if len(spans) != 3 {
+		t.Fatalf("stored %d spans, want 3", len(spans))
+	}
+}
+
+func TestHTTPReceiver_DryRunReportsWithoutStoring(t *testing.T) {
+	spanStore := NewSpanStore()
+	exporter := &recordingExporter{}
+	processor := NewBatchProcessor(exporter, 1, time.Hour)
+	receiver := NewHTTPReceiver(spanStore, WithProcessor(processor))
+	t.Cleanup(func() { _ = receiver.Shutdown(context.Background()) })
+
+	body := `[
+		{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7", "service_name": "api", "start_time_unix_nano": 1},
+		{"trace_id": "not-hex", "span_id": "bad1", "service_name": "api", "start_time_unix_nano": 1},
+		{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "bad2", "duration_nanos": -1}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body))
+	req.Header.Set("X-Dry-Run", "true")
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var resp dryRunResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.Valid || resp.ValidCount != 1 || resp.InvalidCount != 2 || len(resp.Errors) != 2 {
+		t.Fatalf("unexpected report: %+v", resp)
+	}
+
+	first := resp.Errors[0]
+	if first.Index != 1 || first.SpanID != "bad1" || len(first.Errors) != 1 || !strings.Contains(first.Errors[0], "lowercase hex") {
+		t.Fatalf("unexpected report for span 1: %+v", first)
+	}
+	wantSecond := []string{ErrEmptyServiceName.Error(), ErrInvalidStartTime.Error(), "invalid duration_nanos: must be >= 0"}
+	if second := resp.Errors[1]; second.Index != 2 || strings.Join(second.Errors, "|") != strings.Join(wantSecond, "|") {
+		t.Fatalf("report for span 2 = %+v, want errors %v", second, wantSecond)
+	}
+
+	if _, ok := spanStore.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736"); ok {
+		t.Fatalf("dry run stored spans")
+	}
+	if err := processor.Flush(context.Background()); err != nil || exporter.SpanCount() != 0 {
+		t.Fatalf("dry run forwarded %d spans to the processor (flush err %v)", exporter.SpanCount(), err)
+	}
+}
+
+func TestHTTPReceiver_DryRunAllClear(t *testing.T) {
+	spanStore := NewSpanStore()
+	receiver := NewHTTPReceiver(spanStore)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans?dry_run=true", strings.NewReader(receiverTestBody))
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	assertJSONEqual(t, rr.Body.Bytes(), []byte(`{"valid": true, "valid_count": 2, "invalid_count": 0, "errors": []}`))
+	if _, ok := spanStore.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736"); ok {
+		t.Fatalf("dry run stored spans")
+	}
+}
+
+func TestHTTPReceiver_BatchLargerThanBurst(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	limiter := NewRateLimiter(1, 2)
+	limiter.clock = clock
+	spanStore := NewSpanStore()
+	exporter := &recordingExporter{}
+	processor := NewBatchProcessor(exporter, 1, time.Hour)
+	receiver := NewHTTPReceiver(spanStore, WithRateLimiter(limiter), WithProcessor(processor))
+	t.Cleanup(func() { _ = receiver.Shutdown(context.Background()) })
+	handler := receiver.Handler()
+
+	batch := "[" + rateLimitSpanJSON("0000000000000001", "api") + "," +
+		rateLimitSpanJSON("0000000000000002", "api") + "," +
+		rateLimitSpanJSON("0000000000000003", "api") + "]"
+
+	code, resp := postReceiveBody(t, handler, batch)
+	if code != http.StatusAccepted || resp.AcceptedCount != 2 || resp.RateLimitedCount != 1 || resp.RejectedCount != 1 {
+		t.Fatalf("first post = %d %+v, want %d with 2 accepted and 1 rate limited", code, resp, http.StatusAccepted)
+	}
+
+	clock.Advance(time.Second)
+	code, resp = postReceiveBody(t, handler, batch)
+	if code != http.StatusAccepted || resp.AcceptedCount != 3 || resp.RejectedCount != 0 {
+		t.Fatalf("retry = %d %+v, want %d with all 3 accepted", code, resp, http.StatusAccepted)
+	}
+
+	seen := map[string]int{}
+	for _, batch := range exporter.Batches() {
+		for _, span := range batch {
+			seen[span.SpanID]++
+		}
+	}
+	if len(seen) != 3 {
+		t.Fatalf("exported %v, want 3 distinct spans", seen)
+	}
+	for spanID, n := range seen {
+		if n != 1 {
+			t.Fatalf("span %s exported %d times, want 1", spanID, n)
+		}
+	}
+}
+
+func TestHTTPReceiver_RateLimitOnlyRejectsNoisyService(t *testing.T) {
+	spanStore := NewSpanStore()
+	handler := NewHTTPReceiver(spanStore, WithRateLimiter(NewRateLimiter(0, 1))).Handler()
+
+	batch := "[" + rateLimitSpanJSON("0000000000000001", "noisy") + "," +
+		rateLimitSpanJSON("0000000000000002", "noisy") + "," +
+		rateLimitSpanJSON("0000000000000003", "quiet") + "]"
+	code, resp := postReceiveBody(t, handler, batch)
+	if code != http.StatusAccepted || resp.AcceptedCount != 2 || resp.RateLimitedCount != 1 {
+		t.Fatalf("post = %d %+v, want %d with 2 accepted and 1 rate limited", code, resp, http.StatusAccepted)
+	}
+	if spanStore.Has("4bf92f3577b34da6a3ce929d0e0e4736", "0000000000000002") {
+		t.Fatalf("over-limit noisy span was stored")
+	}
+	if !spanStore.Has("4bf92f3577b34da6a3ce929d0e0e4736", "0000000000000003") {
+		t.Fatalf("quiet span was not stored")
+	}
+}
+
+func rateLimitSpanJSON(spanID, service string) string {
+	return `{"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"` + spanID +
+		`","service_name":"` + service + `","operation_name":"predict","start_time_unix_nano":100}`
+}
+
+func postReceiveBody(t *testing.T, handler http.Handler, body string) (int, receiveResponse) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body)))
+	var resp receiveResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return rr.Code, resp
+}
@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+type IDGenerator interface {
+	NewTraceID() string
+	NewSpanID() string
+}
+
+var defaultIDGenerator IDGenerator = RandomIDGenerator{}
+
+// RandomIDGenerator produces W3C-sized random IDs: 128-bit trace IDs and 64-bit
+// span IDs, hex encoded.
+type RandomIDGenerator struct{}
+
+func (RandomIDGenerator) NewTraceID() string {
+	return GenerateTraceID()
+}
+
+func (RandomIDGenerator) NewSpanID() string {
+	return GenerateSpanID()
+}
+
+// SequentialIDGenerator yields predictable, valid IDs (…0001, …0002, …) for
+// tests and golden files. Trace and span IDs count independently.
+type SequentialIDGenerator struct {
+	traces atomic.Uint64
+	spans  atomic.Uint64
+}
+
+func (g *SequentialIDGenerator) NewTraceID() string {
+	return fmt.Sprintf("%032x", g.traces.Add(1))
+}
+
+func (g *SequentialIDGenerator) NewSpanID() string {
+	return fmt.Sprintf("%016x", g.spans.Add(1))
+}
+
+// WithIDGenerator makes the span draw its trace and span IDs from ids. A trace
+// ID inherited through WithParent is kept.
+func WithIDGenerator(ids IDGenerator) SpanOption {
+	return func(s *Span) {
+		if ids != nil {
+			s.ids = ids
+		}
+	}
+}
+
+// GenerateTraceID returns a random 128-bit trace ID as 32 lowercase hex
+// characters, matching the W3C trace-id format.
+func GenerateTraceID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// GenerateSpanID returns a random 64-bit span ID as 16 lowercase hex
+// characters, matching the W3C parent-id format.
+func GenerateSpanID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// TraceIDBytes decodes the hex TraceID into its 16 raw bytes, the form OTLP
+// protobuf uses. Base64 APIs can encode the result directly.
+func (s *Span) TraceIDBytes() ([]byte, error) {
+	if s == nil {
+		return nil, ErrNilSpan
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return decodeID(s.TraceID, traceIDHexLen, ErrInvalidTraceID)
+}
+
+// SpanIDBytes decodes the hex SpanID into its 8 raw bytes.
+func (s *Span) SpanIDBytes() ([]byte, error) {
+	if s == nil {
+		return nil, ErrNilSpan
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return decodeID(s.SpanID, spanIDHexLen, ErrInvalidSpanID)
+}
+
+// SetTraceIDBytes sets TraceID from 16 raw bytes.
+func (s *Span) SetTraceIDBytes(id []byte) error {
+	if s == nil {
+		return ErrNilSpan
+	}
+	if len(id) != traceIDHexLen/2 {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidTraceID, len(id), traceIDHexLen/2)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.TraceID = hex.EncodeToString(id)
+	return nil
+}
+
+// SetSpanIDBytes sets SpanID from 8 raw bytes.
+func (s *Span) SetSpanIDBytes(id []byte) error {
+	if s == nil {
+		return ErrNilSpan
+	}
+	if len(id) != spanIDHexLen/2 {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidSpanID, len(id), spanIDHexLen/2)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SpanID = hex.EncodeToString(id)
+	return nil
+}
+
+func decodeID(id string, hexLen int, errInvalid error) ([]byte, error) {
+	if len(id) != hexLen {
+		return nil, fmt.Errorf("%w: %q must be %d hex characters", errInvalid, id, hexLen)
+	}
+	raw, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not hex", errInvalid, id)
+	}
+	return raw, nil
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewSpan_DefaultIDsAreW3CSized(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !isLowerHex(span.TraceID, traceIDHexLen) {
+		t.Fatalf("trace id %q is not %d lowercase hex characters", span.TraceID, traceIDHexLen)
+	}
+	if !isLowerHex(span.SpanID, spanIDHexLen) {
+		t.Fatalf("span id %q is not %d lowercase hex characters", span.SpanID, spanIDHexLen)
+	}
+}
+
+func TestWithIDGenerator_Sequential(t *testing.T) {
+	ids := &SequentialIDGenerator{}
+
+	root, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithIDGenerator(ids))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	child, err := NewChildSpan(root, "tokenizer", "encode", WithIDGenerator(ids))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	other, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithIDGenerator(ids))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if root.TraceID != "00000000000000000000000000000001" || root.SpanID != "0000000000000001" {
+		t.Fatalf("root ids = %s/%s", root.TraceID, root.SpanID)
+	}
+	if child.TraceID != root.TraceID || child.SpanID != "0000000000000002" || child.ParentSpanID != root.SpanID {
+		t.Fatalf("child ids = %s/%s parent=%s", child.TraceID, child.SpanID, child.ParentSpanID)
+	}
+	if other.TraceID != "00000000000000000000000000000002" || other.SpanID != "0000000000000003" {
+		t.Fatalf("other ids = %s/%s", other.TraceID, other.SpanID)
+	}
+	if err := root.Validate(); err != nil {
+		t.Fatalf("sequential ids should validate: %v", err)
+	}
+}
+
+func TestSpan_IDBytesRoundTrip(t *testing.T) {
+	span := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+
+	traceID, err := span.TraceIDBytes()
+	if err != nil || len(traceID) != 16 {
+		t.Fatalf("TraceIDBytes = %x, %v, want 16 bytes", traceID, err)
+	}
+	spanID, err := span.SpanIDBytes()
+	if err != nil || len(spanID) != 8 {
+		t.Fatalf("SpanIDBytes = %x, %v, want 8 bytes", spanID, err)
+	}
+
+	var out Span
+	if err := out.SetTraceIDBytes(traceID); err != nil {
+		t.Fatalf("SetTraceIDBytes returned error: %v", err)
+	}
+	if err := out.SetSpanIDBytes(spanID); err != nil {
+		t.Fatalf("SetSpanIDBytes returned error: %v", err)
+	}
+	if out.TraceID != span.TraceID || out.SpanID != span.SpanID {
+		t.Fatalf("round trip = %s/%s, want %s/%s", out.TraceID, out.SpanID, span.TraceID, span.SpanID)
+	}
+}
+
+func TestSpan_IDBytesRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "short trace id", err: errOf((&Span{TraceID: "abcd"}).TraceIDBytes()), want: ErrInvalidTraceID},
+		{name: "non-hex trace id", err: errOf((&Span{TraceID: "zzf92f3577b34da6a3ce929d0e0e4736"}).TraceIDBytes()), want: ErrInvalidTraceID},
+		{name: "long span id", err: errOf((&Span{SpanID: "00f067aa0ba902b700"}).SpanIDBytes()), want: ErrInvalidSpanID},
+		{name: "set trace id 8 bytes", err: (&Span{}).SetTraceIDBytes(make([]byte, 8)), want: ErrInvalidTraceID},
+		{name: "set span id 16 bytes", err: (&Span{}).SetSpanIDBytes(make([]byte, 16)), want: ErrInvalidSpanID},
+		{name: "set span id nil", err: (&Span{}).SetSpanIDBytes(nil), want: ErrInvalidSpanID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.want) {
+				t.Fatalf("error = %v, want %v", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func errOf(_ []byte, err error) error {
+	return err
+}
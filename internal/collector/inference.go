@@ -0,0 +1,41 @@
+package collector
+
+import "time"
+
+// Well-known attribute keys for LLM inference spans. Using these instead of
+// ad-hoc names keeps queries working across services.
+const (
+	AttrLLMModel              = "llm.model"
+	AttrLLMPromptTokens       = "llm.usage.prompt_tokens"
+	AttrLLMCompletionTokens   = "llm.usage.completion_tokens"
+	AttrLLMTotalTokens        = "llm.usage.total_tokens"
+	AttrLLMTimeToFirstTokenNs = "llm.latency.time_to_first_token_ns"
+)
+
+// SetModel updates both ModelName and the AttrLLMModel attribute.
+func (s *Span) SetModel(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.ModelName = name
+	s.mu.Unlock()
+
+	s.SetAttribute(AttrLLMModel, name)
+}
+
+func (s *Span) SetTokenCounts(prompt, completion int) {
+	if s == nil {
+		return
+	}
+	s.SetAttribute(AttrLLMPromptTokens, int64(prompt))
+	s.SetAttribute(AttrLLMCompletionTokens, int64(completion))
+	s.SetAttribute(AttrLLMTotalTokens, int64(prompt+completion))
+}
+
+func (s *Span) SetLatencyToFirstToken(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.SetAttribute(AttrLLMTimeToFirstTokenNs, d.Nanoseconds())
+}
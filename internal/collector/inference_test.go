@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpanInferenceHelpers(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.SetModel("llama-3-70b")
+	span.SetTokenCounts(120, 30)
+	span.SetLatencyToFirstToken(250 * time.Millisecond)
+
+	want := map[string]any{
+		"llm.model":                          "llama-3-70b",
+		"llm.usage.prompt_tokens":            int64(120),
+		"llm.usage.completion_tokens":        int64(30),
+		"llm.usage.total_tokens":             int64(150),
+		"llm.latency.time_to_first_token_ns": int64(250 * time.Millisecond),
+	}
+	for key, value := range want {
+		if got, ok := span.Attributes[key]; !ok || got != value {
+			t.Fatalf("%s = %v (%T), want %v (%T)", key, got, got, value, value)
+		}
+	}
+	if span.ModelName != "llama-3-70b" {
+		t.Fatalf("ModelName = %q, want llama-3-70b", span.ModelName)
+	}
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// KeyNormalizer rewrites attribute keys sent in different styles (llmModel,
+// LLM_MODEL, llm-model) to one canonical key (llm.model) so queries are not
+// fragmented. A key matches a canonical key when both are equal ignoring case
+// and the separators '.', '_', '-' and ' '.
+type KeyNormalizer struct {
+	canonical      map[string]string // folded key -> canonical key
+	explicit       map[string]string
+	originalPrefix string
+}
+
+// NewKeyNormalizer maps every style of the given canonical keys onto them.
+func NewKeyNormalizer(canonical ...string) *KeyNormalizer {
+	n := &KeyNormalizer{
+		canonical: make(map[string]string, len(canonical)),
+		explicit:  make(map[string]string),
+	}
+	for _, key := range canonical {
+		n.canonical[foldKey(key)] = key
+	}
+	return n
+}
+
+// Map adds an explicit rule for names the folding cannot catch, such as
+// "model" -> "llm.model". Rules are not chained. It returns n for chaining.
+func (n *KeyNormalizer) Map(from, to string) *KeyNormalizer {
+	n.explicit[from] = to
+	return n
+}
+
+// KeepOriginal also stores each renamed value under prefix plus its original
+// key, e.g. "original.llmModel". It returns n for chaining.
+func (n *KeyNormalizer) KeepOriginal(prefix string) *KeyNormalizer {
+	n.originalPrefix = prefix
+	return n
+}
+
+// CanonicalKey returns the key that key is normalized to, which is key itself
+// when no rule matches.
+func (n *KeyNormalizer) CanonicalKey(key string) string {
+	if to, ok := n.explicit[key]; ok {
+		return to
+	}
+	if to, ok := n.canonical[foldKey(key)]; ok {
+		return to
+	}
+	return key
+}
+
+// Process rewrites span's attribute and event attribute keys in place. When
+// a span carries both a canonical key and a variant, the canonical value wins.
+func (n *KeyNormalizer) Process(span *Span) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	n.normalize(span.Attributes)
+	for i := range span.Events {
+		n.normalize(span.Events[i].Attributes)
+	}
+}
+
+func (n *KeyNormalizer) normalize(attrs map[string]any) {
+	var renamed []string
+	for key := range attrs {
+		if n.CanonicalKey(key) != key {
+			renamed = append(renamed, key)
+		}
+	}
+	// Sorted so that when several variants of one key collide the result does
+	// not depend on map order.
+	slices.Sort(renamed)
+	for _, key := range renamed {
+		value := attrs[key]
+		delete(attrs, key)
+		if n.originalPrefix != "" {
+			attrs[n.originalPrefix+key] = value
+		}
+		canonical := n.CanonicalKey(key)
+		if _, exists := attrs[canonical]; !exists {
+			attrs[canonical] = value
+		}
+	}
+}
+
+// Wrap returns an Exporter that normalizes every span before handing the batch
+// to next.
+func (n *KeyNormalizer) Wrap(next Exporter) Exporter {
+	return ExporterFunc(func(ctx context.Context, spans []*Span) error {
+		if len(spans) == 0 {
+			return nil
+		}
+		for _, span := range spans {
+			n.Process(span)
+		}
+		return next.Export(ctx, spans)
+	})
+}
+
+func foldKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '_', '-', ' ':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
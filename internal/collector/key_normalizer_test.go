@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeyNormalizer_CollapsesStyles(t *testing.T) {
+	normalizer := NewKeyNormalizer(AttrLLMModel, AttrLLMPromptTokens).Map("model", AttrLLMModel)
+
+	for _, key := range []string{"llm.model", "llmModel", "LLM_MODEL", "llm-model", "LlmModel", "model"} {
+		span := &Span{Attributes: map[string]any{key: "gpt-4o-mini", "region": "us"}}
+		normalizer.Process(span)
+
+		want := map[string]any{AttrLLMModel: "gpt-4o-mini", "region": "us"}
+		if !reflect.DeepEqual(span.Attributes, want) {
+			t.Fatalf("%s normalized to %v, want %v", key, span.Attributes, want)
+		}
+	}
+
+	if got := normalizer.CanonicalKey("llmUsagePromptTokens"); got != AttrLLMPromptTokens {
+		t.Fatalf("CanonicalKey(llmUsagePromptTokens) = %q, want %q", got, AttrLLMPromptTokens)
+	}
+}
+
+func TestKeyNormalizer_CanonicalWinsAndOriginalKept(t *testing.T) {
+	normalizer := NewKeyNormalizer(AttrLLMModel).KeepOriginal("original.")
+	span := &Span{
+		Attributes: map[string]any{AttrLLMModel: "gpt-4o", "llmModel": "stale", "tokens": int64(3)},
+		Events:     []Event{{Name: "first_token", Attributes: map[string]any{"LLM_MODEL": "gpt-4o"}}},
+	}
+
+	normalizer.Process(span)
+
+	want := map[string]any{AttrLLMModel: "gpt-4o", "original.llmModel": "stale", "tokens": int64(3)}
+	if !reflect.DeepEqual(span.Attributes, want) {
+		t.Fatalf("attributes = %v, want %v", span.Attributes, want)
+	}
+	wantEvent := map[string]any{AttrLLMModel: "gpt-4o", "original.LLM_MODEL": "gpt-4o"}
+	if !reflect.DeepEqual(span.Events[0].Attributes, wantEvent) {
+		t.Fatalf("event attributes = %v, want %v", span.Events[0].Attributes, wantEvent)
+	}
+}
+
+func TestKeyNormalizer_Wrap(t *testing.T) {
+	exporter := &recordingExporter{}
+	wrapped := NewKeyNormalizer("tenant.id").Wrap(exporter)
+
+	if err := wrapped.Export(context.Background(), []*Span{{Attributes: map[string]any{"tenantId": "acme"}}}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := exporter.Batches()[0][0].Attributes["tenant.id"]; got != "acme" {
+		t.Fatalf("tenant.id = %v, want acme", got)
+	}
+}
+
+func TestHTTPReceiver_NormalizesKeys(t *testing.T) {
+	spanStore := NewSpanStore()
+	receiver := NewHTTPReceiver(spanStore, WithKeyNormalizer(NewKeyNormalizer(AttrLLMModel)))
+
+	body := `[{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7", "service_name": "api",
+		"start_time_unix_nano": 1, "attributes": {"LLM_MODEL": "gpt-4o-mini"}}]`
+	req := httptest.NewRequest(http.MethodPost, "/v1/spans", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	receiver.Handler().ServeHTTP(rr, req)
+
+	spans, ok := spanStore.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736")
+	if !ok || len(spans) != 1 {
+		t.Fatalf("stored spans = %d (status %d), want 1", len(spans), rr.Code)
+	}
+	if got := spans[0].Attributes[AttrLLMModel]; got != "gpt-4o-mini" {
+		t.Fatalf("%s = %v, want gpt-4o-mini", AttrLLMModel, got)
+	}
+}
@@ -0,0 +1,69 @@
+package collector
+
+import "strings"
+
+// SpanKind values line up with the OTLP SpanKind enum so they can be exported
+// without a lookup table.
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	case SpanKindProducer:
+		return "producer"
+	case SpanKindConsumer:
+		return "consumer"
+	default:
+		return "unspecified"
+	}
+}
+
+func ParseSpanKind(raw string) SpanKind {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "internal":
+		return SpanKindInternal
+	case "server":
+		return SpanKindServer
+	case "client":
+		return SpanKindClient
+	case "producer":
+		return SpanKindProducer
+	case "consumer":
+		return SpanKindConsumer
+	default:
+		return SpanKindUnspecified
+	}
+}
+
+func (k SpanKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *SpanKind) UnmarshalText(text []byte) error {
+	*k = ParseSpanKind(string(text))
+	return nil
+}
+
+func (s *Span) SetKind(kind SpanKind) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Kind = kind
+}
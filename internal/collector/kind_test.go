@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewSpan_DefaultsToInternalKind(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.Kind != SpanKindInternal {
+		t.Fatalf("kind = %v, want %v", span.Kind, SpanKindInternal)
+	}
+
+	child, err := NewChildSpan(span, "tokenizer", "encode")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if child.Kind != SpanKindInternal {
+		t.Fatalf("child kind = %v, want %v", child.Kind, SpanKindInternal)
+	}
+}
+
+func TestSpanSetKind(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.SetKind(SpanKindServer)
+	if span.Kind != SpanKindServer {
+		t.Fatalf("kind = %v, want %v", span.Kind, SpanKindServer)
+	}
+}
+
+func TestSpanKind_JSONRoundTrip(t *testing.T) {
+	for _, kind := range []SpanKind{SpanKindUnspecified, SpanKindInternal, SpanKindServer, SpanKindClient, SpanKindProducer, SpanKindConsumer} {
+		data, err := json.Marshal(kind)
+		if err != nil {
+			t.Fatalf("marshal %v: %v", kind, err)
+		}
+		var decoded SpanKind
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal %s: %v", data, err)
+		}
+		if decoded != kind {
+			t.Fatalf("round trip %v -> %s -> %v", kind, data, decoded)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package collector
+
+import "maps"
+
+// Link points at a span in another (or the same) trace, e.g. each request that
+// was folded into one batched inference call.
+type Link struct {
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+func (s *Span) AddLink(traceID, spanID string, attrs map[string]any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Links = append(s.Links, Link{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		Attributes: maps.Clone(attrs),
+	})
+}
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpanAddLink(t *testing.T) {
+	span, err := NewSpan("inference-api", "batch-predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.Links != nil {
+		t.Fatalf("expected nil links after NewSpan, got %v", span.Links)
+	}
+
+	span.AddLink("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7", map[string]any{"batch.index": int64(0)})
+	span.AddLink("5bf92f3577b34da6a3ce929d0e0e4736", "10f067aa0ba902b7", nil)
+
+	if got, want := len(span.Links), 2; got != want {
+		t.Fatalf("link count = %d, want %d", got, want)
+	}
+	if span.Links[0].TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Links[0].SpanID != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected first link: %+v", span.Links[0])
+	}
+	if got := span.Links[0].Attributes["batch.index"]; got != int64(0) {
+		t.Fatalf("batch.index = %v, want 0", got)
+	}
+	if err := span.Validate(); err != nil {
+		t.Fatalf("expected valid span, got %v", err)
+	}
+}
+
+func TestSpanValidate_RejectsIncompleteLinks(t *testing.T) {
+	span, err := NewSpan("inference-api", "batch-predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.AddLink("", "00f067aa0ba902b7", nil)
+	span.AddLink("4bf92f3577b34da6a3ce929d0e0e4736", "", nil)
+
+	err = span.Validate()
+	if err == nil {
+		t.Fatalf("expected validation error for incomplete links")
+	}
+	for _, want := range []string{"links[0].trace_id", "links[1].span_id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestToOTLP_IncludesLinks(t *testing.T) {
+	span := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", ServiceName: "svc"}
+	span.AddLink("5bf92f3577b34da6a3ce929d0e0e4736", "10f067aa0ba902b7", nil)
+
+	got := ToOTLP([]*Span{span}).ResourceSpans[0].ScopeSpans[0].Spans[0].Links
+	if len(got) != 1 || got[0].TraceID != "5bf92f3577b34da6a3ce929d0e0e4736" || got[0].SpanID != "10f067aa0ba902b7" {
+		t.Fatalf("unexpected OTLP links: %+v", got)
+	}
+}
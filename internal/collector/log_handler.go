@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log attribute keys added by LogHandler.
+const (
+	LogKeyTraceID = "trace_id"
+	LogKeySpanID  = "span_id"
+)
+
+// LogHandler wraps an slog.Handler and adds trace_id and span_id to every
+// record logged with a context carrying a span (see ContextWithSpan), so log
+// lines can be joined to traces. Records without a span pass through as is.
+// After WithGroup the IDs land inside the group, like any other attribute.
+type LogHandler struct {
+	next slog.Handler
+}
+
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if span, ok := SpanFromContext(ctx); ok {
+		span.mu.Lock()
+		traceID, spanID := span.TraceID, span.SpanID
+		span.mu.Unlock()
+
+		record = record.Clone()
+		record.AddAttrs(slog.String(LogKeyTraceID, traceID), slog.String(LogKeySpanID, spanID))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var line map[string]any
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("decode log line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLogHandler_AddsSpanIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "router")
+
+	span := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	logger.InfoContext(ContextWithSpan(context.Background(), span), "routing request")
+	logger.InfoContext(context.Background(), "no span here")
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2", len(lines))
+	}
+	inside, outside := lines[0], lines[1]
+	if inside[LogKeyTraceID] != span.TraceID || inside[LogKeySpanID] != span.SpanID {
+		t.Fatalf("log inside span = %v, want trace and span IDs", inside)
+	}
+	if inside["component"] != "router" {
+		t.Fatalf("log inside span lost attributes from With: %v", inside)
+	}
+	if _, ok := outside[LogKeyTraceID]; ok {
+		t.Fatalf("log outside span = %v, want no trace_id", outside)
+	}
+	if _, ok := outside[LogKeySpanID]; ok {
+		t.Fatalf("log outside span = %v, want no span_id", outside)
+	}
+}
+
+func TestLogHandler_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	logger.InfoContext(ContextWithSpan(context.Background(), &Span{TraceID: "t", SpanID: "s"}), "ignored")
+
+	if buf.Len() != 0 {
+		t.Fatalf("info record written below warn level: %s", buf.String())
+	}
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// AttrMergeConflicts lists, sorted, the span fields on which Merge found two
+// different non-empty values and kept the receiver's.
+const AttrMergeConflicts = "span.merge_conflicts"
+
+// Merge folds other, a second report of the same logical span (say from the
+// client and the server), into s. Empty fields are filled from other,
+// Attributes, Resource, Events and Links are unioned, and the timing widens to
+// the earliest start and the latest end. When both spans set a scalar field to
+// different values s wins and the field name is added to AttrMergeConflicts.
+func (s *Span) Merge(other *Span) {
+	if s == nil || other == nil || s == other {
+		return
+	}
+	other = other.Clone()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var conflicts []string
+	mergeField := func(name string, dst *string, src string) {
+		switch {
+		case src == "":
+		case *dst == "":
+			*dst = src
+		case *dst != src:
+			conflicts = append(conflicts, name)
+		}
+	}
+	mergeField("trace_id", &s.TraceID, other.TraceID)
+	mergeField("span_id", &s.SpanID, other.SpanID)
+	mergeField("parent_span_id", &s.ParentSpanID, other.ParentSpanID)
+	mergeField("service_name", &s.ServiceName, other.ServiceName)
+	mergeField("operation_name", &s.OperationName, other.OperationName)
+	mergeField("model_name", &s.ModelName, other.ModelName)
+
+	switch {
+	case other.Kind == SpanKindUnspecified:
+	case s.Kind == SpanKindUnspecified:
+		s.Kind = other.Kind
+	case s.Kind != other.Kind:
+		conflicts = append(conflicts, "kind")
+	}
+	switch {
+	case other.Status == StatusUnset:
+	case s.Status == StatusUnset:
+		s.Status, s.StatusDescription = other.Status, other.StatusDescription
+	case s.Status != other.Status:
+		conflicts = append(conflicts, "status")
+	}
+
+	if other.StartTimeUnixNano != 0 {
+		end := max(s.StartTimeUnixNano+s.DurationNanos, other.StartTimeUnixNano+other.DurationNanos)
+		if s.StartTimeUnixNano == 0 || other.StartTimeUnixNano < s.StartTimeUnixNano {
+			s.StartTimeUnixNano = other.StartTimeUnixNano
+			// The start no longer matches s's own clock reading.
+			s.start = time.Time{}
+		}
+		s.DurationNanos = end - s.StartTimeUnixNano
+	}
+
+	s.Attributes = unionAttributes(s.Attributes, other.Attributes)
+	if len(other.Resource) > 0 {
+		// Copy rather than write into s.Resource, which a Tracer may share.
+		resource := maps.Clone(other.Resource)
+		maps.Copy(resource, s.Resource)
+		s.Resource = resource
+	}
+	for _, event := range other.Events {
+		if !slices.ContainsFunc(s.Events, func(e Event) bool {
+			return e.Name == event.Name && e.TimeUnixNano == event.TimeUnixNano
+		}) {
+			s.Events = append(s.Events, event)
+		}
+	}
+	for _, link := range other.Links {
+		if !slices.ContainsFunc(s.Links, func(l Link) bool {
+			return l.TraceID == link.TraceID && l.SpanID == link.SpanID
+		}) {
+			s.Links = append(s.Links, link)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		if previous, ok := s.Attributes[AttrMergeConflicts].([]string); ok {
+			conflicts = append(conflicts, previous...)
+		}
+		slices.Sort(conflicts)
+		s.Attributes[AttrMergeConflicts] = slices.Compact(conflicts)
+	}
+}
+
+// unionAttributes adds the keys of src missing from dst, allocating dst if
+// needed. Keys already in dst keep their value.
+func unionAttributes(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for key, value := range src {
+		if _, ok := dst[key]; !ok {
+			dst[key] = value
+		}
+	}
+	return dst
+}
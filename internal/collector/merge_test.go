@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpanMerge_UnionsAndFillsEmptyFields(t *testing.T) {
+	client := &Span{
+		TraceID:           "t1",
+		SpanID:            "a",
+		ServiceName:       "gateway",
+		Kind:              SpanKindUnspecified,
+		StartTimeUnixNano: 100,
+		DurationNanos:     50, // ends at 150
+		Attributes:        map[string]any{"net.peer": "api", "shared": "client"},
+		Events:            []Event{{Name: "sent", TimeUnixNano: 101}},
+		Links:             []Link{{TraceID: "t0", SpanID: "x"}},
+		Resource:          Resource{"host": "edge-1"},
+	}
+	server := &Span{
+		TraceID:           "t1",
+		SpanID:            "a",
+		OperationName:     "predict",
+		ModelName:         "gpt-4o-mini",
+		Kind:              SpanKindServer,
+		StartTimeUnixNano: 110,
+		DurationNanos:     60, // ends at 170
+		Status:            StatusOK,
+		Attributes:        map[string]any{"http.route": "/predict", "shared": "server"},
+		Events:            []Event{{Name: "sent", TimeUnixNano: 101}, {Name: "received", TimeUnixNano: 112}},
+		Links:             []Link{{TraceID: "t0", SpanID: "x"}, {TraceID: "t0", SpanID: "y"}},
+		Resource:          Resource{"host": "node-7", "region": "us"},
+	}
+
+	client.Merge(server)
+
+	if client.OperationName != "predict" || client.ModelName != "gpt-4o-mini" || client.Kind != SpanKindServer || client.Status != StatusOK {
+		t.Fatalf("empty fields not filled: %+v", client)
+	}
+	if client.StartTimeUnixNano != 100 || client.DurationNanos != 70 {
+		t.Fatalf("timing = start %d duration %d, want 100 and 70", client.StartTimeUnixNano, client.DurationNanos)
+	}
+	wantAttrs := map[string]any{"net.peer": "api", "http.route": "/predict", "shared": "client"}
+	if !reflect.DeepEqual(client.Attributes, wantAttrs) {
+		t.Fatalf("attributes = %v, want %v", client.Attributes, wantAttrs)
+	}
+	if len(client.Events) != 2 || client.Events[1].Name != "received" {
+		t.Fatalf("events = %+v, want sent and received once each", client.Events)
+	}
+	if len(client.Links) != 2 || client.Links[1].SpanID != "y" {
+		t.Fatalf("links = %+v, want x and y once each", client.Links)
+	}
+	if want := (Resource{"host": "edge-1", "region": "us"}); !reflect.DeepEqual(client.Resource, want) {
+		t.Fatalf("resource = %v, want %v", client.Resource, want)
+	}
+	if _, ok := server.Resource["region"]; !ok || server.Resource["host"] != "node-7" {
+		t.Fatalf("Merge modified other's resource: %v", server.Resource)
+	}
+}
+
+func TestSpanMerge_ConflictsPreferReceiver(t *testing.T) {
+	client := &Span{TraceID: "t1", SpanID: "a", ServiceName: "gateway", Kind: SpanKindClient, Status: StatusError, StatusDescription: "timeout"}
+	server := &Span{TraceID: "t1", SpanID: "a", ServiceName: "inference-api", Kind: SpanKindServer, Status: StatusOK}
+
+	client.Merge(server)
+
+	if client.ServiceName != "gateway" || client.Kind != SpanKindClient || client.Status != StatusError || client.StatusDescription != "timeout" {
+		t.Fatalf("receiver values not kept: %+v", client)
+	}
+	want := []string{"kind", "service_name", "status"}
+	if got := client.Attributes[AttrMergeConflicts]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s = %v, want %v", AttrMergeConflicts, got, want)
+	}
+}
+
+func TestSpanMerge_NilAndSelf(t *testing.T) {
+	span := &Span{SpanID: "a", DurationNanos: 5}
+	span.Merge(nil)
+	span.Merge(span)
+	var nilSpan *Span
+	nilSpan.Merge(span)
+
+	if span.DurationNanos != 5 || len(span.Attributes) != 0 {
+		t.Fatalf("span changed: %+v", span)
+	}
+}
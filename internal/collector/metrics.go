@@ -0,0 +1,237 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrUnknownOperation = errors.New("no metrics for operation")
+
+var defaultLatencyBounds = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type operationKey struct {
+	service   string
+	operation string
+}
+
+type operationStats struct {
+	count    int64
+	errors   int64
+	sumNanos int64
+	maxNanos int64
+	// buckets[i] counts samples <= bounds[i]; the extra last slot is +Inf.
+	buckets []int64
+	// exemplars[i] is the trace ID of the latest sample in buckets[i].
+	exemplars []string
+}
+
+// BucketCount is one cumulative histogram bucket, Prometheus style: Count is the
+// number of samples with latency <= UpperBound. The last bucket has
+// UpperBound 0 to stand for +Inf.
+type BucketCount struct {
+	UpperBound time.Duration
+	Count      int64
+}
+
+type OperationMetrics struct {
+	ServiceName   string
+	OperationName string
+	Count         int64
+	ErrorCount    int64
+	SumNanos      int64
+	Buckets       []BucketCount
+}
+
+// MetricsAggregator derives request, error and latency metrics from spans. It
+// implements Exporter so it can sit in the pipeline next to real exporters.
+type MetricsAggregator struct {
+	bounds []time.Duration
+
+	mu  sync.Mutex
+	ops map[operationKey]*operationStats
+}
+
+// NewMetricsAggregator uses bounds as the histogram bucket upper bounds; nil
+// selects a default 5ms..10s ladder. bounds must be sorted ascending.
+func NewMetricsAggregator(bounds []time.Duration) *MetricsAggregator {
+	if len(bounds) == 0 {
+		bounds = defaultLatencyBounds
+	}
+	return &MetricsAggregator{
+		bounds: bounds,
+		ops:    make(map[operationKey]*operationStats),
+	}
+}
+
+func (m *MetricsAggregator) Export(_ context.Context, spans []*Span) error {
+	for _, span := range spans {
+		m.Consume(span)
+	}
+	return nil
+}
+
+func (m *MetricsAggregator) Consume(span *Span) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	key := operationKey{service: span.ServiceName, operation: span.OperationName}
+	traceID := span.TraceID
+	duration := span.DurationNanos
+	failed := span.Status == StatusError
+	span.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.ops[key]
+	if !ok {
+		stats = &operationStats{
+			buckets:   make([]int64, len(m.bounds)+1),
+			exemplars: make([]string, len(m.bounds)+1),
+		}
+		m.ops[key] = stats
+	}
+	stats.count++
+	stats.sumNanos += duration
+	stats.maxNanos = max(stats.maxNanos, duration)
+	if failed {
+		stats.errors++
+	}
+	i := m.bucketIndex(duration)
+	stats.buckets[i]++
+	if traceID != "" {
+		stats.exemplars[i] = traceID
+	}
+}
+
+func (m *MetricsAggregator) bucketIndex(durationNanos int64) int {
+	return sort.Search(len(m.bounds), func(i int) bool {
+		return durationNanos <= int64(m.bounds[i])
+	})
+}
+
+// Percentile estimates the q-quantile (0 < q <= 1) latency of an operation by
+// interpolating linearly inside the histogram bucket holding that rank, so the
+// result is only as precise as the bucket bounds. Samples above the last bound
+// are interpolated up to the largest latency seen.
+func (m *MetricsAggregator) Percentile(service, operation string, q float64) (time.Duration, error) {
+	if q <= 0 || q > 1 || math.IsNaN(q) {
+		return 0, fmt.Errorf("percentile %v out of range (0, 1]", q)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.ops[operationKey{service: service, operation: operation}]
+	if !ok || stats.count == 0 {
+		return 0, fmt.Errorf("%w %s/%s", ErrUnknownOperation, service, operation)
+	}
+
+	i, cumulative, rank := stats.rankBucket(q)
+	if i < 0 {
+		return time.Duration(stats.maxNanos), nil
+	}
+	count := stats.buckets[i]
+	var lower, upper float64
+	if i > 0 {
+		lower = float64(m.bounds[i-1])
+	}
+	if i < len(m.bounds) {
+		upper = float64(m.bounds[i])
+	} else {
+		upper = float64(stats.maxNanos)
+	}
+	fraction := (rank - float64(cumulative)) / float64(count)
+	return time.Duration(lower + (upper-lower)*fraction), nil
+}
+
+// rankBucket returns the index of the bucket holding the q-quantile sample, the
+// number of samples in earlier buckets, and the rank itself. The index is -1
+// if no bucket reaches the rank.
+func (s *operationStats) rankBucket(q float64) (int, int64, float64) {
+	rank := math.Ceil(q * float64(s.count))
+	var cumulative int64
+	for i, count := range s.buckets {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		return i, cumulative, rank
+	}
+	return -1, cumulative, rank
+}
+
+// Exemplar returns a trace ID from the histogram bucket holding the q-quantile
+// latency of an operation, for jumping from a metric to a representative trace.
+// It reports false if q is out of range or no traced sample is in that bucket.
+func (m *MetricsAggregator) Exemplar(service, operation string, q float64) (string, bool) {
+	if q <= 0 || q > 1 || math.IsNaN(q) {
+		return "", false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.ops[operationKey{service: service, operation: operation}]
+	if !ok || stats.count == 0 {
+		return "", false
+	}
+	i, _, _ := stats.rankBucket(q)
+	if i < 0 || stats.exemplars[i] == "" {
+		return "", false
+	}
+	return stats.exemplars[i], true
+}
+
+// Snapshot returns the current counters sorted by service then operation.
+func (m *MetricsAggregator) Snapshot() []OperationMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]OperationMetrics, 0, len(m.ops))
+	for key, stats := range m.ops {
+		buckets := make([]BucketCount, len(stats.buckets))
+		var cumulative int64
+		for i, count := range stats.buckets {
+			cumulative += count
+			buckets[i].Count = cumulative
+			if i < len(m.bounds) {
+				buckets[i].UpperBound = m.bounds[i]
+			}
+		}
+		out = append(out, OperationMetrics{
+			ServiceName:   key.service,
+			OperationName: key.operation,
+			Count:         stats.count,
+			ErrorCount:    stats.errors,
+			SumNanos:      stats.sumNanos,
+			Buckets:       buckets,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ServiceName != out[j].ServiceName {
+			return out[i].ServiceName < out[j].ServiceName
+		}
+		return out[i].OperationName < out[j].OperationName
+	})
+	return out
+}
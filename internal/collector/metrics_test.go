@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMetricsAggregator_CountsRequestsAndErrors(t *testing.T) {
+	m := NewMetricsAggregator([]time.Duration{10 * time.Millisecond, 100 * time.Millisecond})
+
+	spans := []*Span{
+		{ServiceName: "inference-api", OperationName: "predict", DurationNanos: int64(5 * time.Millisecond), Status: StatusOK},
+		{ServiceName: "inference-api", OperationName: "predict", DurationNanos: int64(50 * time.Millisecond), Status: StatusError},
+		{ServiceName: "inference-api", OperationName: "predict", DurationNanos: int64(time.Second), Status: StatusOK},
+		{ServiceName: "tokenizer", OperationName: "encode", DurationNanos: int64(time.Millisecond)},
+	}
+	if err := m.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	snapshot := m.Snapshot()
+	if got, want := len(snapshot), 2; got != want {
+		t.Fatalf("snapshot size = %d, want %d", got, want)
+	}
+
+	predict := snapshot[0]
+	if predict.ServiceName != "inference-api" || predict.OperationName != "predict" {
+		t.Fatalf("unexpected first entry: %+v", predict)
+	}
+	if predict.Count != 3 || predict.ErrorCount != 1 {
+		t.Fatalf("predict count/errors = %d/%d, want 3/1", predict.Count, predict.ErrorCount)
+	}
+	if got, want := predict.SumNanos, int64(1055*time.Millisecond); got != want {
+		t.Fatalf("predict sum = %d, want %d", got, want)
+	}
+
+	wantBuckets := []BucketCount{
+		{UpperBound: 10 * time.Millisecond, Count: 1},
+		{UpperBound: 100 * time.Millisecond, Count: 2},
+		{UpperBound: 0, Count: 3},
+	}
+	for i, want := range wantBuckets {
+		if predict.Buckets[i] != want {
+			t.Fatalf("bucket %d = %+v, want %+v", i, predict.Buckets[i], want)
+		}
+	}
+
+	encode := snapshot[1]
+	if encode.Count != 1 || encode.ErrorCount != 0 {
+		t.Fatalf("encode count/errors = %d/%d, want 1/0", encode.Count, encode.ErrorCount)
+	}
+}
+
+func TestMetricsAggregator_Percentile(t *testing.T) {
+	m := NewMetricsAggregator(nil)
+
+	// 100 samples at 1ms..100ms, one per millisecond.
+	for i := 1; i <= 100; i++ {
+		m.Consume(&Span{ServiceName: "api", OperationName: "predict", DurationNanos: int64(time.Duration(i) * time.Millisecond)})
+	}
+
+	tests := []struct {
+		q            float64
+		lower, upper time.Duration
+	}{
+		{q: 0.50, lower: 25 * time.Millisecond, upper: 50 * time.Millisecond},
+		{q: 0.95, lower: 50 * time.Millisecond, upper: 100 * time.Millisecond},
+		{q: 0.99, lower: 50 * time.Millisecond, upper: 100 * time.Millisecond},
+		{q: 1, lower: 100 * time.Millisecond, upper: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, err := m.Percentile("api", "predict", tt.q)
+		if err != nil {
+			t.Fatalf("Percentile(%v) returned error: %v", tt.q, err)
+		}
+		if got < tt.lower || got > tt.upper {
+			t.Fatalf("Percentile(%v) = %v, want within [%v, %v]", tt.q, got, tt.lower, tt.upper)
+		}
+	}
+}
+
+func TestMetricsAggregator_PercentileOverflowBucket(t *testing.T) {
+	m := NewMetricsAggregator([]time.Duration{time.Millisecond})
+	m.Consume(&Span{ServiceName: "api", OperationName: "predict", DurationNanos: int64(40 * time.Millisecond)})
+
+	got, err := m.Percentile("api", "predict", 0.99)
+	if err != nil {
+		t.Fatalf("Percentile returned error: %v", err)
+	}
+	if got < time.Millisecond || got > 40*time.Millisecond {
+		t.Fatalf("Percentile = %v, want within [1ms, 40ms]", got)
+	}
+}
+
+func TestMetricsAggregator_PercentileErrors(t *testing.T) {
+	m := NewMetricsAggregator(nil)
+	m.Consume(&Span{ServiceName: "api", OperationName: "predict", DurationNanos: 1})
+
+	if _, err := m.Percentile("api", "missing", 0.5); !errors.Is(err, ErrUnknownOperation) {
+		t.Fatalf("unknown key error = %v, want %v", err, ErrUnknownOperation)
+	}
+	if _, err := m.Percentile("api", "predict", 1.5); err == nil {
+		t.Fatalf("expected error for q out of range")
+	}
+}
+
+func TestMetricsAggregator_Exemplar(t *testing.T) {
+	m := NewMetricsAggregator([]time.Duration{10 * time.Millisecond, 100 * time.Millisecond})
+	fast := map[string]bool{"fast-1": true, "fast-2": true}
+	spans := []*Span{
+		{TraceID: "fast-1", ServiceName: "api", OperationName: "predict", DurationNanos: int64(2 * time.Millisecond)},
+		{TraceID: "fast-2", ServiceName: "api", OperationName: "predict", DurationNanos: int64(3 * time.Millisecond)},
+		{TraceID: "slow-1", ServiceName: "api", OperationName: "predict", DurationNanos: int64(80 * time.Millisecond)},
+		{TraceID: "huge-1", ServiceName: "api", OperationName: "predict", DurationNanos: int64(time.Second)},
+	}
+	if err := m.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	tests := []struct {
+		q    float64
+		want func(string) bool
+	}{
+		{q: 0.5, want: func(id string) bool { return fast[id] }},
+		{q: 0.75, want: func(id string) bool { return id == "slow-1" }},
+		{q: 1, want: func(id string) bool { return id == "huge-1" }},
+	}
+	for _, tt := range tests {
+		got, ok := m.Exemplar("api", "predict", tt.q)
+		if !ok || !tt.want(got) {
+			t.Fatalf("Exemplar(q=%v) = %q, %v; not a span from that bucket", tt.q, got, ok)
+		}
+	}
+}
+
+func TestMetricsAggregator_ExemplarMissing(t *testing.T) {
+	m := NewMetricsAggregator(nil)
+	m.Consume(&Span{ServiceName: "api", OperationName: "untraced", DurationNanos: 1})
+
+	if _, ok := m.Exemplar("api", "predict", 0.5); ok {
+		t.Fatalf("Exemplar for unknown operation reported ok")
+	}
+	if _, ok := m.Exemplar("api", "untraced", 0.5); ok {
+		t.Fatalf("Exemplar for span without trace ID reported ok")
+	}
+	if _, ok := m.Exemplar("api", "untraced", 1.5); ok {
+		t.Fatalf("Exemplar for q out of range reported ok")
+	}
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"net/http"
+)
+
+const (
+	AttrHTTPMethod     = "http.method"
+	AttrHTTPRoute      = "http.route"
+	AttrHTTPStatusCode = "http.status_code"
+
+	defaultMiddlewareService = "http-server"
+)
+
+type MiddlewareOption func(*middleware)
+
+// WithServiceName sets the ServiceName of spans started by Middleware.
+func WithServiceName(name string) MiddlewareOption {
+	return func(m *middleware) {
+		if name != "" {
+			m.serviceName = name
+		}
+	}
+}
+
+// WithSpanHandler registers fn to receive each span after it has ended, for
+// example Collector.Enqueue or BatchProcessor.Add.
+func WithSpanHandler(fn func(*Span)) MiddlewareOption {
+	return func(m *middleware) {
+		m.onEnd = fn
+	}
+}
+
+type middleware struct {
+	next        http.Handler
+	serviceName string
+	onEnd       func(*Span)
+}
+
+// Middleware wraps next so each request runs inside a server span. An incoming
+// traceparent header continues the caller's trace; a missing or malformed one
+// starts a new trace. Handlers can reach the span with SpanFromContext.
+func Middleware(next http.Handler, opts ...MiddlewareOption) http.Handler {
+	m := &middleware{
+		next:        next,
+		serviceName: defaultMiddlewareService,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	span := newSpan(m.serviceName, req.Method+" "+req.URL.Path, "")
+	span.Kind = SpanKindServer
+	_ = span.continueTrace(req.Header.Get("traceparent"))
+	applySpanOptions(span, nil)
+	span.Attributes[AttrHTTPMethod] = req.Method
+	span.Attributes[AttrHTTPRoute] = req.URL.Path
+
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		span.SetAttribute(AttrHTTPStatusCode, int64(rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(StatusError, http.StatusText(rec.status))
+		}
+		_ = span.End()
+		if m.onEnd != nil {
+			m.onEnd(span)
+		}
+	}()
+
+	m.next.ServeHTTP(rec, req.WithContext(ContextWithSpan(req.Context(), span)))
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
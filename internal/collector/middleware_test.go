@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveThroughMiddleware(t *testing.T, status int, traceparent string) (*Span, *Span) {
+	t.Helper()
+
+	var inHandler, ended *Span
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inHandler, _ = SpanFromContext(r.Context())
+		w.WriteHeader(status)
+	}), WithServiceName("api"), WithSpanHandler(func(span *Span) {
+		ended = span
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/predict", nil)
+	if traceparent != "" {
+		req.Header.Set("traceparent", traceparent)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if ended == nil {
+		t.Fatalf("span handler was not called")
+	}
+	return inHandler, ended
+}
+
+func TestMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	inHandler, span := serveThroughMiddleware(t, http.StatusOK,
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	if inHandler != span {
+		t.Fatalf("handler saw a different span than the one ended")
+	}
+	if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("TraceID = %q", span.TraceID)
+	}
+	if span.ParentSpanID != "00f067aa0ba902b7" {
+		t.Fatalf("ParentSpanID = %q", span.ParentSpanID)
+	}
+	if span.Kind != SpanKindServer {
+		t.Fatalf("Kind = %v, want %v", span.Kind, SpanKindServer)
+	}
+	if span.Status != StatusUnset {
+		t.Fatalf("Status = %v, want %v", span.Status, StatusUnset)
+	}
+	if span.ServiceName != "api" || span.OperationName != "GET /predict" {
+		t.Fatalf("names = %q/%q", span.ServiceName, span.OperationName)
+	}
+	if !span.Ended() {
+		t.Fatalf("span was not ended")
+	}
+}
+
+func TestMiddleware_ServerErrorSetsStatus(t *testing.T) {
+	_, span := serveThroughMiddleware(t, http.StatusBadGateway, "")
+
+	if span.Status != StatusError {
+		t.Fatalf("Status = %v, want %v", span.Status, StatusError)
+	}
+	if got := span.Attributes[AttrHTTPStatusCode]; got != int64(http.StatusBadGateway) {
+		t.Fatalf("%s = %v, want %d", AttrHTTPStatusCode, got, http.StatusBadGateway)
+	}
+	if span.ParentSpanID != "" || len(span.TraceID) != 32 {
+		t.Fatalf("expected a new root trace, got trace=%q parent=%q", span.TraceID, span.ParentSpanID)
+	}
+}
+
+func TestMiddleware_ClientErrorLeavesStatusUnset(t *testing.T) {
+	_, span := serveThroughMiddleware(t, http.StatusNotFound, "")
+
+	if span.Status != StatusUnset {
+		t.Fatalf("Status = %v, want %v", span.Status, StatusUnset)
+	}
+}
@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"context"
+	"errors"
+)
+
+// MultiExporter fans each batch out to several exporters in order. Every
+// exporter is called even when an earlier one fails; their errors are joined.
+type MultiExporter struct {
+	exporters []Exporter
+}
+
+func NewMultiExporter(exporters ...Exporter) *MultiExporter {
+	kept := make([]Exporter, 0, len(exporters))
+	for _, exporter := range exporters {
+		if exporter != nil {
+			kept = append(kept, exporter)
+		}
+	}
+	return &MultiExporter{exporters: kept}
+}
+
+func (m *MultiExporter) Export(ctx context.Context, spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, exporter := range m.exporters {
+		if err := exporter.Export(ctx, spans); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMultiExporter_CallsAllAndJoinsErrors(t *testing.T) {
+	var order []string
+	failure := errors.New("otlp unavailable")
+
+	failing := ExporterFunc(func(context.Context, []*Span) error {
+		order = append(order, "failing")
+		return failure
+	})
+	healthy := &recordingExporter{}
+	tracking := ExporterFunc(func(ctx context.Context, spans []*Span) error {
+		order = append(order, "healthy")
+		return healthy.Export(ctx, spans)
+	})
+
+	multi := NewMultiExporter(failing, nil, tracking)
+	err := multi.Export(context.Background(), []*Span{{SpanID: "a"}})
+
+	if !errors.Is(err, failure) {
+		t.Fatalf("Export error = %v, want it to wrap %v", err, failure)
+	}
+	if want := []string{"failing", "healthy"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("call order = %v, want %v", order, want)
+	}
+	if healthy.SpanCount() != 1 {
+		t.Fatalf("healthy exporter got %d spans, want 1", healthy.SpanCount())
+	}
+}
+
+func TestMultiExporter_NoErrors(t *testing.T) {
+	multi := NewMultiExporter(&recordingExporter{}, &recordingExporter{})
+	if err := multi.Export(context.Background(), []*Span{{SpanID: "a"}}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+}
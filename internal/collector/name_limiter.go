@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// OverflowOperationName replaces operation names beyond a service's budget.
+	OverflowOperationName = "other"
+	// AttrOriginalOperationName keeps the name a span had before it was folded.
+	AttrOriginalOperationName = "span.original_name"
+)
+
+// NameLimiter caps the number of distinct OperationName values per service.
+// The first maxPerService names seen for a service pass through; any later new
+// name is folded into OverflowOperationName so downstream metrics stay bounded.
+type NameLimiter struct {
+	maxPerService int
+
+	mu    sync.Mutex
+	names map[string]map[string]struct{}
+}
+
+func NewNameLimiter(maxPerService int) *NameLimiter {
+	if maxPerService <= 0 {
+		maxPerService = 100
+	}
+	return &NameLimiter{
+		maxPerService: maxPerService,
+		names:         make(map[string]map[string]struct{}),
+	}
+}
+
+// Process rewrites span in place when its name is over budget.
+func (l *NameLimiter) Process(span *Span) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	if l.allow(span.ServiceName, span.OperationName) {
+		return
+	}
+	if span.Attributes == nil {
+		span.Attributes = make(map[string]any)
+	}
+	span.Attributes[AttrOriginalOperationName] = span.OperationName
+	span.OperationName = OverflowOperationName
+}
+
+func (l *NameLimiter) allow(service, name string) bool {
+	if name == OverflowOperationName {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen, ok := l.names[service]
+	if !ok {
+		seen = make(map[string]struct{})
+		l.names[service] = seen
+	}
+	if _, ok := seen[name]; ok {
+		return true
+	}
+	if len(seen) >= l.maxPerService {
+		return false
+	}
+	seen[name] = struct{}{}
+	return true
+}
+
+// Wrap returns an Exporter that normalizes span names before handing the batch
+// to next.
+func (l *NameLimiter) Wrap(next Exporter) Exporter {
+	return ExporterFunc(func(ctx context.Context, spans []*Span) error {
+		if len(spans) == 0 {
+			return nil
+		}
+		for _, span := range spans {
+			l.Process(span)
+		}
+		return next.Export(ctx, spans)
+	})
+}
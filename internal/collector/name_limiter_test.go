@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestNameLimiter_FoldsNamesBeyondBudget(t *testing.T) {
+	limiter := NewNameLimiter(3)
+
+	var folded int
+	for i := range 10 {
+		span := &Span{ServiceName: "api", OperationName: fmt.Sprintf("GET /users/%d", i)}
+		limiter.Process(span)
+		if i < 3 {
+			if span.OperationName != fmt.Sprintf("GET /users/%d", i) {
+				t.Fatalf("span %d renamed to %q within budget", i, span.OperationName)
+			}
+			continue
+		}
+		if span.OperationName != OverflowOperationName {
+			t.Fatalf("span %d OperationName = %q, want %q", i, span.OperationName, OverflowOperationName)
+		}
+		if got := span.Attributes[AttrOriginalOperationName]; got != fmt.Sprintf("GET /users/%d", i) {
+			t.Fatalf("span %d original name = %v", i, got)
+		}
+		folded++
+	}
+	if folded != 7 {
+		t.Fatalf("folded = %d, want 7", folded)
+	}
+}
+
+func TestNameLimiter_KnownNamesAndServicesIndependent(t *testing.T) {
+	limiter := NewNameLimiter(1)
+
+	first := &Span{ServiceName: "api", OperationName: "predict"}
+	limiter.Process(first)
+	again := &Span{ServiceName: "api", OperationName: "predict"}
+	limiter.Process(again)
+	if again.OperationName != "predict" {
+		t.Fatalf("known name folded to %q", again.OperationName)
+	}
+
+	other := &Span{ServiceName: "worker", OperationName: "embed"}
+	limiter.Process(other)
+	if other.OperationName != "embed" {
+		t.Fatalf("other service name folded to %q", other.OperationName)
+	}
+}
+
+func TestNameLimiter_Wrap(t *testing.T) {
+	exporter := &recordingExporter{}
+	wrapped := NewNameLimiter(1).Wrap(exporter)
+
+	spans := []*Span{
+		{ServiceName: "api", OperationName: "a"},
+		{ServiceName: "api", OperationName: "b"},
+	}
+	if err := wrapped.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	batches := exporter.Batches()
+	if len(batches) != 1 || batches[0][1].OperationName != OverflowOperationName {
+		t.Fatalf("exported batch not normalized: %v", batches)
+	}
+}
@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"slices"
+	"time"
+)
+
+// SpanOption customizes a span at construction time. Options run after the
+// defaults are filled in, so they override them.
+type SpanOption func(*Span)
+
+// WithParent places the span in the parent's trace. A nil parent is ignored and
+// the span stays a root.
+func WithParent(parent *Span) SpanOption {
+	return func(s *Span) {
+		if parent == nil {
+			return
+		}
+		parent.mu.Lock()
+		defer parent.mu.Unlock()
+
+		s.TraceID = parent.TraceID
+		s.ParentSpanID = parent.SpanID
+		s.Sampled = parent.Sampled
+		s.parent = parent
+	}
+}
+
+// WithInheritedAttributes copies the named attributes from the parent, such as
+// llm.model or tenant.id, so they need not be repeated on every child. Keys the
+// parent lacks or the child already sets are skipped. It has no effect on a root
+// span.
+func WithInheritedAttributes(keys ...string) SpanOption {
+	return func(s *Span) {
+		s.inherit = append(s.inherit, keys...)
+	}
+}
+
+func (s *Span) inheritAttributes(parent *Span, keys []string) {
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+
+	for _, key := range keys {
+		value, ok := parent.Attributes[key]
+		if !ok {
+			continue
+		}
+		if _, set := s.Attributes[key]; set {
+			continue
+		}
+		if s.Attributes == nil {
+			s.Attributes = make(map[string]any, len(keys))
+		}
+		if values, isSlice := value.([]string); isSlice {
+			value = slices.Clone(values)
+		}
+		s.Attributes[key] = value
+	}
+}
+
+func WithKind(kind SpanKind) SpanOption {
+	return func(s *Span) {
+		s.Kind = kind
+	}
+}
+
+// WithAttributes copies attrs onto the span, overwriting keys already present.
+func WithAttributes(attrs map[string]any) SpanOption {
+	return func(s *Span) {
+		if s.Attributes == nil {
+			s.Attributes = make(map[string]any, len(attrs))
+		}
+		for key, value := range attrs {
+			s.Attributes[key] = value
+		}
+	}
+}
+
+func WithStartTime(startTimeUnixNano int64) SpanOption {
+	return func(s *Span) {
+		s.StartTimeUnixNano = startTimeUnixNano
+	}
+}
+
+// WithDuration fixes the span's duration up front, as SetDuration does.
+// Together with WithStartTime it builds a span with fully explicit timing.
+func WithDuration(d time.Duration) SpanOption {
+	return func(s *Span) {
+		s.DurationNanos = d.Nanoseconds()
+		s.durationSet = true
+	}
+}
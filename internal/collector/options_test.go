@@ -0,0 +1,157 @@
+package collector
+
+import "testing"
+
+func TestNewSpan_NoOptionsKeepsDefaults(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.Kind != SpanKindInternal || span.ParentSpanID != "" || len(span.Attributes) != 0 {
+		t.Fatalf("unexpected defaults: %+v", span)
+	}
+}
+
+func TestWithParent(t *testing.T) {
+	parent, err := NewSpan("gateway", "route", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithParent(parent))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.TraceID != parent.TraceID || span.ParentSpanID != parent.SpanID {
+		t.Fatalf("span not linked to parent: trace=%q parent=%q", span.TraceID, span.ParentSpanID)
+	}
+
+	root, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithParent(nil))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if root.TraceID == "" || root.ParentSpanID != "" {
+		t.Fatalf("nil parent should leave a root span: %+v", root)
+	}
+}
+
+func TestWithKind(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithKind(SpanKindClient))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.Kind != SpanKindClient {
+		t.Fatalf("kind = %v, want %v", span.Kind, SpanKindClient)
+	}
+}
+
+func TestWithAttributes(t *testing.T) {
+	attrs := map[string]any{"gpu.id": "gpu-0"}
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithAttributes(attrs))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	attrs["gpu.id"] = "gpu-9"
+
+	if got, _ := span.GetAttribute("gpu.id"); got != "gpu-0" {
+		t.Fatalf("gpu.id = %v, want gpu-0", got)
+	}
+}
+
+func TestWithStartTime(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithStartTime(1234))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.StartTimeUnixNano != 1234 {
+		t.Fatalf("start = %d, want 1234", span.StartTimeUnixNano)
+	}
+}
+
+func TestNewSpan_CombinedOptions(t *testing.T) {
+	parent, err := NewSpan("gateway", "route", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini",
+		WithParent(parent),
+		WithKind(SpanKindServer),
+		WithAttributes(map[string]any{"tenant.id": "acme"}),
+		WithStartTime(42),
+	)
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if span.TraceID != parent.TraceID || span.ParentSpanID != parent.SpanID {
+		t.Fatalf("span not linked to parent")
+	}
+	if span.Kind != SpanKindServer {
+		t.Fatalf("kind = %v, want %v", span.Kind, SpanKindServer)
+	}
+	if got, _ := span.GetAttribute("tenant.id"); got != "acme" {
+		t.Fatalf("tenant.id = %v, want acme", got)
+	}
+	if span.StartTimeUnixNano != 42 {
+		t.Fatalf("start = %d, want 42", span.StartTimeUnixNano)
+	}
+}
+
+func TestWithInheritedAttributes(t *testing.T) {
+	parent, err := NewSpan("gateway", "route", "gpt-4o-mini", WithAttributes(map[string]any{
+		"llm.model":  "gpt-4o-mini",
+		"tenant.id":  "acme",
+		"tags":       []string{"a", "b"},
+		"http.route": "/predict",
+	}))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	child, err := NewChildSpan(parent, "inference-api", "predict",
+		WithInheritedAttributes("llm.model", "tags", "missing"),
+		WithInheritedAttributes("tenant.id"))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(child.Attributes) != 3 || child.Attributes["llm.model"] != "gpt-4o-mini" || child.Attributes["tenant.id"] != "acme" {
+		t.Fatalf("child attributes = %v, want only llm.model, tags and tenant.id", child.Attributes)
+	}
+	if _, ok := child.Attributes["http.route"]; ok {
+		t.Fatalf("unlisted attribute http.route was inherited")
+	}
+
+	child.Attributes["tenant.id"] = "other"
+	child.Attributes["tags"].([]string)[0] = "z"
+	if parent.Attributes["tenant.id"] != "acme" || parent.Attributes["tags"].([]string)[0] != "a" {
+		t.Fatalf("mutating child changed parent: %v", parent.Attributes)
+	}
+}
+
+func TestWithInheritedAttributes_ChildValueWins(t *testing.T) {
+	parent, err := NewSpan("gateway", "route", "gpt-4o-mini", WithAttributes(map[string]any{"tenant.id": "acme"}))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	child, err := NewSpan("inference-api", "predict", "gpt-4o-mini",
+		WithInheritedAttributes("tenant.id"),
+		WithAttributes(map[string]any{"tenant.id": "override"}),
+		WithParent(parent))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if got := child.Attributes["tenant.id"]; got != "override" {
+		t.Fatalf("tenant.id = %v, want override", got)
+	}
+
+	root, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithInheritedAttributes("tenant.id"))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(root.Attributes) != 0 {
+		t.Fatalf("root attributes = %v, want none", root.Attributes)
+	}
+}
@@ -0,0 +1,259 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const otlpScopeName = "infertrace"
+
+// The OTLP types below mirror the OTLP/JSON encoding of
+// ExportTraceServiceRequest closely enough for an OpenTelemetry Collector's
+// otlphttp receiver to accept them. 64-bit integers are strings, as the
+// protobuf JSON mapping requires.
+type OTLPTraces struct {
+	ResourceSpans []OTLPResourceSpans `json:"resourceSpans"`
+}
+
+type OTLPResourceSpans struct {
+	Resource   OTLPResource     `json:"resource"`
+	ScopeSpans []OTLPScopeSpans `json:"scopeSpans"`
+}
+
+type OTLPResource struct {
+	Attributes []OTLPKeyValue `json:"attributes"`
+}
+
+type OTLPScopeSpans struct {
+	Scope OTLPScope  `json:"scope"`
+	Spans []OTLPSpan `json:"spans"`
+}
+
+type OTLPScope struct {
+	Name string `json:"name"`
+}
+
+type OTLPSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []OTLPKeyValue `json:"attributes,omitempty"`
+	Events            []OTLPEvent    `json:"events,omitempty"`
+	Links             []OTLPLink     `json:"links,omitempty"`
+	Status            OTLPStatus     `json:"status"`
+}
+
+type OTLPEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+type OTLPLink struct {
+	TraceID    string         `json:"traceId"`
+	SpanID     string         `json:"spanId"`
+	Attributes []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+// OTLPStatus always carries Message, empty when the span has no description,
+// so consumers see a complete status object for every span.
+type OTLPStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// OTLP status codes; OTLP/JSON encodes enums as their integer values.
+const (
+	OTLPStatusCodeUnset = 0 // STATUS_CODE_UNSET
+	OTLPStatusCodeOK    = 1 // STATUS_CODE_OK
+	OTLPStatusCodeError = 2 // STATUS_CODE_ERROR
+)
+
+func toOTLPStatus(code StatusCode, description string) OTLPStatus {
+	status := OTLPStatus{Code: OTLPStatusCodeUnset, Message: description}
+	switch code {
+	case StatusOK:
+		status.Code = OTLPStatusCodeOK
+	case StatusError:
+		status.Code = OTLPStatusCodeError
+	}
+	return status
+}
+
+type OTLPKeyValue struct {
+	Key   string       `json:"key"`
+	Value OTLPAnyValue `json:"value"`
+}
+
+type OTLPAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *OTLPArrayValue `json:"arrayValue,omitempty"`
+}
+
+type OTLPArrayValue struct {
+	Values []OTLPAnyValue `json:"values"`
+}
+
+type OTLPOption func(*otlpConfig)
+
+type otlpConfig struct {
+	precision time.Duration
+}
+
+// WithTimestampPrecision rounds every emitted timestamp to the nearest multiple
+// of unit, such as time.Millisecond, for downstreams that store coarser times.
+// Start and end are rounded separately, so a duration shorter than unit may
+// come out as zero. The fields stay in nanoseconds; the default is no rounding.
+func WithTimestampPrecision(unit time.Duration) OTLPOption {
+	return func(c *otlpConfig) {
+		c.precision = unit
+	}
+}
+
+func (c otlpConfig) timestamp(unixNano int64) string {
+	if unit := int64(c.precision); unit > 1 {
+		unixNano = (unixNano + unit/2) / unit * unit
+	}
+	return strconv.FormatInt(unixNano, 10)
+}
+
+// ToOTLP groups spans into one resource block per ServiceName, in the order each
+// service is first seen. The block's attributes come from the first span's
+// Resource, so shared resource keys are emitted once per service.
+func ToOTLP(spans []*Span, opts ...OTLPOption) OTLPTraces {
+	var cfg otlpConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	out := OTLPTraces{ResourceSpans: []OTLPResourceSpans{}}
+	index := make(map[string]int)
+
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		i, ok := index[span.ServiceName]
+		if !ok {
+			i = len(out.ResourceSpans)
+			index[span.ServiceName] = i
+			out.ResourceSpans = append(out.ResourceSpans, OTLPResourceSpans{
+				Resource:   otlpResource(span),
+				ScopeSpans: []OTLPScopeSpans{{Scope: OTLPScope{Name: otlpScopeName}}},
+			})
+		}
+		scope := &out.ResourceSpans[i].ScopeSpans[0]
+		scope.Spans = append(scope.Spans, toOTLPSpan(span, cfg))
+	}
+	return out
+}
+
+func otlpResource(span *Span) OTLPResource {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	attrs := make(map[string]any, len(span.Resource)+1)
+	for key, value := range span.Resource {
+		attrs[key] = value
+	}
+	attrs["service.name"] = span.ServiceName
+	return OTLPResource{Attributes: otlpAttributes(attrs)}
+}
+
+func toOTLPSpan(span *Span, cfg otlpConfig) OTLPSpan {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	attrs := make(map[string]any, len(span.Attributes)+1)
+	if span.ModelName != "" {
+		attrs[AttrLLMModel] = span.ModelName
+	}
+	for key, value := range span.Attributes {
+		attrs[key] = value
+	}
+
+	out := OTLPSpan{
+		TraceID:           span.TraceID,
+		SpanID:            span.SpanID,
+		ParentSpanID:      span.ParentSpanID,
+		Name:              span.OperationName,
+		Kind:              int(span.Kind),
+		StartTimeUnixNano: cfg.timestamp(span.StartTimeUnixNano),
+		EndTimeUnixNano:   cfg.timestamp(span.StartTimeUnixNano + span.DurationNanos),
+		Attributes:        otlpAttributes(attrs),
+		Status:            toOTLPStatus(span.Status, span.StatusDescription),
+	}
+	for _, event := range span.Events {
+		out.Events = append(out.Events, OTLPEvent{
+			TimeUnixNano: cfg.timestamp(event.TimeUnixNano),
+			Name:         event.Name,
+			Attributes:   otlpAttributes(event.Attributes),
+		})
+	}
+	for _, link := range span.Links {
+		out.Links = append(out.Links, OTLPLink{
+			TraceID:    link.TraceID,
+			SpanID:     link.SpanID,
+			Attributes: otlpAttributes(link.Attributes),
+		})
+	}
+	return out
+}
+
+// otlpAttributes sorts keys so the output is stable across runs.
+func otlpAttributes(attrs map[string]any) []OTLPKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make([]OTLPKeyValue, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, otlpKeyValue(key, attrs[key]))
+	}
+	return out
+}
+
+func otlpKeyValue(key string, value any) OTLPKeyValue {
+	return OTLPKeyValue{Key: key, Value: otlpAnyValue(value)}
+}
+
+func otlpAnyValue(value any) OTLPAnyValue {
+	var v OTLPAnyValue
+	switch typed := value.(type) {
+	case string:
+		v.StringValue = &typed
+	case bool:
+		v.BoolValue = &typed
+	case int:
+		s := strconv.Itoa(typed)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(typed, 10)
+		v.IntValue = &s
+	case float64:
+		v.DoubleValue = &typed
+	case []string:
+		values := make([]OTLPAnyValue, 0, len(typed))
+		for _, item := range typed {
+			values = append(values, otlpAnyValue(item))
+		}
+		v.ArrayValue = &OTLPArrayValue{Values: values}
+	default:
+		s := fmt.Sprint(typed)
+		v.StringValue = &s
+	}
+	return v
+}
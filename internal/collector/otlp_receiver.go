@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"context"
+	"encoding/hex"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OTLPTraceServer implements the OTLP TraceService so OpenTelemetry SDKs can
+// point their gRPC exporter straight at the collector.
+type OTLPTraceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	store   *SpanStore
+	limiter *RateLimiter
+	onDrop  DropFunc
+}
+
+type OTLPServerOption func(*OTLPTraceServer)
+
+// WithOTLPRateLimiter drops spans from services over their rate. When every
+// span in a request was rate limited the server answers ResourceExhausted so
+// well-behaved SDKs back off and retry; otherwise the dropped spans are
+// reported through PartialSuccess. Spans already stored, as on a retry, are
+// accepted without taking a token.
+func WithOTLPRateLimiter(limiter *RateLimiter) OTLPServerOption {
+	return func(s *OTLPTraceServer) {
+		s.limiter = limiter
+	}
+}
+
+// WithOTLPOnDrop calls fn for every span the server rejects, with
+// DropInvalid or DropRateLimited.
+func WithOTLPOnDrop(fn DropFunc) OTLPServerOption {
+	return func(s *OTLPTraceServer) {
+		s.onDrop = fn
+	}
+}
+
+func NewOTLPTraceServer(store *SpanStore, opts ...OTLPServerOption) *OTLPTraceServer {
+	if store == nil {
+		store = NewSpanStore()
+	}
+	s := &OTLPTraceServer{
+		store: store,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *OTLPTraceServer) Export(
+	_ context.Context,
+	req *coltracepb.ExportTraceServiceRequest,
+) (*coltracepb.ExportTraceServiceResponse, error) {
+	var rejected, limited, accepted int64
+
+	for _, resourceSpans := range req.GetResourceSpans() {
+		serviceName := otlpServiceName(resourceSpans.GetResource().GetAttributes())
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			for _, pbSpan := range scopeSpans.GetSpans() {
+				span := fromOTLPSpan(serviceName, pbSpan)
+				if err := span.Validate(); err != nil {
+					rejected++
+					s.onDrop.call(span, DropInvalid)
+					continue
+				}
+				if !s.store.Has(span.TraceID, span.SpanID) && !s.limiter.Allow(span.ServiceName) {
+					limited++
+					s.onDrop.call(span, DropRateLimited)
+					continue
+				}
+				s.store.Add(span)
+				accepted++
+			}
+		}
+	}
+
+	if limited > 0 && accepted == 0 && rejected == 0 {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded: %d spans dropped", limited)
+	}
+
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	switch {
+	case limited > 0:
+		resp.PartialSuccess = &coltracepb.ExportTracePartialSuccess{
+			RejectedSpans: rejected + limited,
+			ErrorMessage:  "rate limit exceeded for one or more services",
+		}
+	case rejected > 0:
+		resp.PartialSuccess = &coltracepb.ExportTracePartialSuccess{
+			RejectedSpans: rejected,
+			ErrorMessage:  "one or more spans failed validation",
+		}
+	}
+	return resp, nil
+}
+
+func otlpServiceName(attrs []*commonpb.KeyValue) string {
+	for _, kv := range attrs {
+		if kv.GetKey() == "service.name" {
+			return kv.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
+func fromOTLPSpan(serviceName string, pbSpan *tracepb.Span) *Span {
+	span := &Span{
+		TraceID:           hex.EncodeToString(pbSpan.GetTraceId()),
+		SpanID:            hex.EncodeToString(pbSpan.GetSpanId()),
+		ParentSpanID:      hex.EncodeToString(pbSpan.GetParentSpanId()),
+		ServiceName:       serviceName,
+		OperationName:     pbSpan.GetName(),
+		Kind:              SpanKind(pbSpan.GetKind()),
+		StartTimeUnixNano: int64(pbSpan.GetStartTimeUnixNano()),
+		Status:            StatusCode(pbSpan.GetStatus().GetCode()),
+		StatusDescription: pbSpan.GetStatus().GetMessage(),
+		Attributes:        fromOTLPAttributes(pbSpan.GetAttributes()),
+		Sampled:           true,
+	}
+	if end := int64(pbSpan.GetEndTimeUnixNano()); end > span.StartTimeUnixNano {
+		span.DurationNanos = end - span.StartTimeUnixNano
+	}
+	if model, ok := span.Attributes[AttrLLMModel].(string); ok {
+		span.ModelName = model
+	}
+	for _, pbEvent := range pbSpan.GetEvents() {
+		span.Events = append(span.Events, Event{
+			Name:         pbEvent.GetName(),
+			TimeUnixNano: int64(pbEvent.GetTimeUnixNano()),
+			Attributes:   fromOTLPAttributes(pbEvent.GetAttributes()),
+		})
+	}
+	for _, pbLink := range pbSpan.GetLinks() {
+		span.Links = append(span.Links, Link{
+			TraceID:    hex.EncodeToString(pbLink.GetTraceId()),
+			SpanID:     hex.EncodeToString(pbLink.GetSpanId()),
+			Attributes: fromOTLPAttributes(pbLink.GetAttributes()),
+		})
+	}
+	return span
+}
+
+func fromOTLPAttributes(kvs []*commonpb.KeyValue) map[string]any {
+	attrs := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		attrs[kv.GetKey()] = fromOTLPAnyValue(kv.GetValue())
+	}
+	return attrs
+}
+
+func fromOTLPAnyValue(v *commonpb.AnyValue) any {
+	switch typed := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return typed.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return typed.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return typed.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return typed.DoubleValue
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]any, 0, len(typed.ArrayValue.GetValues()))
+		for _, item := range typed.ArrayValue.GetValues() {
+			values = append(values, fromOTLPAnyValue(item))
+		}
+		return values
+	case *commonpb.AnyValue_BytesValue:
+		return hex.EncodeToString(typed.BytesValue)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,231 @@
+package collector
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func dialOTLPTraceServer(t *testing.T, spanStore *SpanStore, opts ...OTLPServerOption) coltracepb.TraceServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(grpcServer, NewOTLPTraceServer(spanStore, opts...))
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return coltracepb.NewTraceServiceClient(conn)
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
+
+func TestOTLPTraceServer_StoresAndMapsSpans(t *testing.T) {
+	spanStore := NewSpanStore()
+	client := dialOTLPTraceServer(t, spanStore)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{{
+					Key:   "service.name",
+					Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "inference-api"}},
+				}},
+			},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{
+					{
+						TraceId:           mustHex(t, traceID),
+						SpanId:            mustHex(t, "00f067aa0ba902b7"),
+						Name:              "predict",
+						Kind:              tracepb.Span_SPAN_KIND_SERVER,
+						StartTimeUnixNano: 1000,
+						EndTimeUnixNano:   1600,
+						Attributes: []*commonpb.KeyValue{
+							{Key: AttrLLMModel, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "gpt-4o-mini"}}},
+							{Key: AttrLLMPromptTokens, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 42}}},
+						},
+						Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "timeout"},
+					},
+					{
+						TraceId:           mustHex(t, traceID),
+						SpanId:            mustHex(t, "b7ad6b7169203331"),
+						ParentSpanId:      mustHex(t, "00f067aa0ba902b7"),
+						Name:              "encode",
+						Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+						StartTimeUnixNano: 1100,
+						EndTimeUnixNano:   1150,
+					},
+					{
+						// Missing trace ID: must be rejected.
+						SpanId:            mustHex(t, "c7ad6b7169203331"),
+						StartTimeUnixNano: 1,
+					},
+				},
+			}},
+		}},
+	}
+
+	resp, err := client.Export(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := resp.GetPartialSuccess().GetRejectedSpans(); got != 1 {
+		t.Fatalf("rejected spans = %d, want 1", got)
+	}
+
+	spans, ok := spanStore.GetTrace(traceID)
+	if !ok || len(spans) != 2 {
+		t.Fatalf("stored spans = %d (ok=%v), want 2", len(spans), ok)
+	}
+
+	root := spans[0]
+	if root.SpanID != "00f067aa0ba902b7" || root.ParentSpanID != "" {
+		t.Fatalf("unexpected root ids: span=%q parent=%q", root.SpanID, root.ParentSpanID)
+	}
+	if root.ServiceName != "inference-api" || root.OperationName != "predict" || root.ModelName != "gpt-4o-mini" {
+		t.Fatalf("unexpected root names: %+v", root)
+	}
+	if root.Kind != SpanKindServer {
+		t.Fatalf("root kind = %v, want %v", root.Kind, SpanKindServer)
+	}
+	if root.StartTimeUnixNano != 1000 || root.DurationNanos != 600 {
+		t.Fatalf("root timing = %d/%d, want 1000/600", root.StartTimeUnixNano, root.DurationNanos)
+	}
+	if root.Status != StatusError || root.StatusDescription != "timeout" {
+		t.Fatalf("root status = %v %q, want error timeout", root.Status, root.StatusDescription)
+	}
+	if got := root.Attributes[AttrLLMPromptTokens]; got != int64(42) {
+		t.Fatalf("%s = %v, want 42", AttrLLMPromptTokens, got)
+	}
+
+	child := spans[1]
+	if child.ParentSpanID != "00f067aa0ba902b7" || child.DurationNanos != 50 || child.Kind != SpanKindInternal {
+		t.Fatalf("unexpected child: %+v", child)
+	}
+}
+
+func otlpRequestForService(t *testing.T, service, spanID string) *coltracepb.ExportTraceServiceRequest {
+	t.Helper()
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{{
+					Key:   "service.name",
+					Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: service}},
+				}},
+			},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{
+					TraceId:           mustHex(t, "4bf92f3577b34da6a3ce929d0e0e4736"),
+					SpanId:            mustHex(t, spanID),
+					Name:              "predict",
+					StartTimeUnixNano: 1000,
+					EndTimeUnixNano:   1100,
+				}},
+			}},
+		}},
+	}
+}
+
+func TestOTLPTraceServer_RateLimitPerService(t *testing.T) {
+	limiter := NewRateLimiter(0, 1)
+	client := dialOTLPTraceServer(t, NewSpanStore(), WithOTLPRateLimiter(limiter))
+	ctx := context.Background()
+
+	if _, err := client.Export(ctx, otlpRequestForService(t, "noisy", "00f067aa0ba902b7")); err != nil {
+		t.Fatalf("first Export returned error: %v", err)
+	}
+	_, err := client.Export(ctx, otlpRequestForService(t, "noisy", "00f067aa0ba902b8"))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("over-limit Export code = %v, want %v", status.Code(err), codes.ResourceExhausted)
+	}
+	if _, err := client.Export(ctx, otlpRequestForService(t, "quiet", "00f067aa0ba902b9")); err != nil {
+		t.Fatalf("other service Export returned error: %v", err)
+	}
+}
+
+func TestOTLPTraceServer_BatchLargerThanBurst(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	limiter := NewRateLimiter(1, 1)
+	limiter.clock = clock
+	store := NewSpanStore()
+	client := dialOTLPTraceServer(t, store, WithOTLPRateLimiter(limiter))
+	ctx := context.Background()
+
+	req := otlpRequestForService(t, "noisy", "00f067aa0ba902b7")
+	second := otlpRequestForService(t, "noisy", "00f067aa0ba902b8").ResourceSpans[0].ScopeSpans[0].Spans[0]
+	req.ResourceSpans[0].ScopeSpans[0].Spans = append(req.ResourceSpans[0].ScopeSpans[0].Spans, second)
+
+	resp, err := client.Export(ctx, req)
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := resp.GetPartialSuccess().GetRejectedSpans(); got != 1 {
+		t.Fatalf("rejected spans = %d, want 1", got)
+	}
+
+	clock.Advance(time.Second)
+	resp, err = client.Export(ctx, req)
+	if err != nil || resp.GetPartialSuccess() != nil {
+		t.Fatalf("retry = %v, %v, want full success", resp, err)
+	}
+	if spans, _ := store.GetTrace("4bf92f3577b34da6a3ce929d0e0e4736"); len(spans) != 2 {
+		t.Fatalf("stored %d spans after retry, want 2", len(spans))
+	}
+}
+
+func TestOTLPTraceServer_RateLimitOnlyRejectsNoisyService(t *testing.T) {
+	store := NewSpanStore()
+	client := dialOTLPTraceServer(t, store, WithOTLPRateLimiter(NewRateLimiter(0, 1)))
+
+	req := otlpRequestForService(t, "noisy", "00f067aa0ba902b7")
+	second := otlpRequestForService(t, "noisy", "00f067aa0ba902b8").ResourceSpans[0].ScopeSpans[0].Spans[0]
+	req.ResourceSpans[0].ScopeSpans[0].Spans = append(req.ResourceSpans[0].ScopeSpans[0].Spans, second)
+	req.ResourceSpans = append(req.ResourceSpans, otlpRequestForService(t, "quiet", "00f067aa0ba902b9").ResourceSpans...)
+
+	resp, err := client.Export(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := resp.GetPartialSuccess().GetRejectedSpans(); got != 1 {
+		t.Fatalf("rejected spans = %d, want 1", got)
+	}
+	if store.Has("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b8") {
+		t.Fatalf("over-limit noisy span was stored")
+	}
+	if !store.Has("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b9") {
+		t.Fatalf("quiet span was not stored")
+	}
+}
@@ -0,0 +1,216 @@
+package collector
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToOTLP_GroupsByServiceAndMapsFields(t *testing.T) {
+	spans := []*Span{
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "00f067aa0ba902b7",
+			ServiceName:       "inference-api",
+			OperationName:     "predict",
+			ModelName:         "gpt-4o-mini",
+			Kind:              SpanKindServer,
+			StartTimeUnixNano: 1000,
+			DurationNanos:     500,
+			Status:            StatusOK,
+			Attributes:        map[string]any{"llm.usage.prompt_tokens": 12},
+		},
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "b7ad6b7169203331",
+			ParentSpanID:      "00f067aa0ba902b7",
+			ServiceName:       "tokenizer",
+			OperationName:     "encode",
+			Kind:              SpanKindInternal,
+			StartTimeUnixNano: 1100,
+			DurationNanos:     50,
+			Status:            StatusError,
+		},
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "c7ad6b7169203331",
+			ParentSpanID:      "00f067aa0ba902b7",
+			ServiceName:       "inference-api",
+			OperationName:     "sample",
+			Kind:              SpanKindClient,
+			StartTimeUnixNano: 1200,
+			DurationNanos:     100,
+			Attributes:        map[string]any{"cache.hit": true},
+		},
+	}
+
+	want := `{
+	"resourceSpans": [
+		{
+			"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "inference-api"}}]},
+			"scopeSpans": [{
+				"scope": {"name": "infertrace"},
+				"spans": [
+					{
+						"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+						"spanId": "00f067aa0ba902b7",
+						"name": "predict",
+						"kind": 2,
+						"startTimeUnixNano": "1000",
+						"endTimeUnixNano": "1500",
+						"attributes": [
+							{"key": "llm.model", "value": {"stringValue": "gpt-4o-mini"}},
+							{"key": "llm.usage.prompt_tokens", "value": {"intValue": "12"}}
+						],
+						"status": {"code": 1, "message": ""}
+					},
+					{
+						"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+						"spanId": "c7ad6b7169203331",
+						"parentSpanId": "00f067aa0ba902b7",
+						"name": "sample",
+						"kind": 3,
+						"startTimeUnixNano": "1200",
+						"endTimeUnixNano": "1300",
+						"attributes": [{"key": "cache.hit", "value": {"boolValue": true}}],
+						"status": {"code": 0, "message": ""}
+					}
+				]
+			}]
+		},
+		{
+			"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "tokenizer"}}]},
+			"scopeSpans": [{
+				"scope": {"name": "infertrace"},
+				"spans": [
+					{
+						"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+						"spanId": "b7ad6b7169203331",
+						"parentSpanId": "00f067aa0ba902b7",
+						"name": "encode",
+						"kind": 1,
+						"startTimeUnixNano": "1100",
+						"endTimeUnixNano": "1150",
+						"status": {"code": 2, "message": ""}
+					}
+				]
+			}]
+		}
+	]
+}`
+
+	got, err := json.Marshal(ToOTLP(spans))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	assertJSONEqual(t, got, []byte(want))
+}
+
+func TestToOTLP_Empty(t *testing.T) {
+	got, err := json.Marshal(ToOTLP(nil))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	assertJSONEqual(t, got, []byte(`{"resourceSpans": []}`))
+}
+
+func assertJSONEqual(t *testing.T, got, want []byte) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("unmarshal got: %v", err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("unmarshal want: %v", err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Fatalf("JSON mismatch:\n got=%s\nwant=%s", got, want)
+	}
+}
+
+func TestToOTLP_Status(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        StatusCode
+		description string
+		want        OTLPStatus
+	}{
+		{name: "unset", code: StatusUnset, want: OTLPStatus{Code: OTLPStatusCodeUnset}},
+		{name: "ok", code: StatusOK, want: OTLPStatus{Code: OTLPStatusCodeOK}},
+		{name: "error with description", code: StatusError, description: "model timeout", want: OTLPStatus{Code: OTLPStatusCodeError, Message: "model timeout"}},
+		{name: "error without description", code: StatusError, want: OTLPStatus{Code: OTLPStatusCodeError}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			span := &Span{TraceID: "t", SpanID: "s", ServiceName: "api"}
+			span.SetStatus(tc.code, tc.description)
+
+			got := ToOTLP([]*Span{span}).ResourceSpans[0].ScopeSpans[0].Spans[0].Status
+			if got != tc.want {
+				t.Fatalf("status = %+v, want %+v", got, tc.want)
+			}
+
+			raw, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var fields map[string]any
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if _, ok := fields["message"]; !ok {
+				t.Fatalf("status JSON %s has no message field", raw)
+			}
+		})
+	}
+}
+
+func TestToOTLP_TimestampPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		unit      time.Duration
+		start     int64
+		duration  int64
+		wantStart string
+		wantEnd   string
+	}{
+		{name: "default nanoseconds", start: 1_000_000_499, duration: 1, wantStart: "1000000499", wantEnd: "1000000500"},
+		{name: "microseconds", unit: time.Microsecond, start: 1_000_000_499, duration: 2_600, wantStart: "1000000000", wantEnd: "1000003000"},
+		{name: "milliseconds round up", unit: time.Millisecond, start: 1_000_500_000, duration: 1_200_000, wantStart: "1001000000", wantEnd: "1002000000"},
+		{name: "sub-unit duration rounds to zero", unit: time.Millisecond, start: 2_000_000_100, duration: 300_000, wantStart: "2000000000", wantEnd: "2000000000"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			span := &Span{TraceID: "t", SpanID: "s", ServiceName: "api", StartTimeUnixNano: tc.start, DurationNanos: tc.duration}
+			var opts []OTLPOption
+			if tc.unit > 0 {
+				opts = append(opts, WithTimestampPrecision(tc.unit))
+			}
+
+			got := ToOTLP([]*Span{span}, opts...).ResourceSpans[0].ScopeSpans[0].Spans[0]
+			if got.StartTimeUnixNano != tc.wantStart || got.EndTimeUnixNano != tc.wantEnd {
+				t.Fatalf("start, end = %s, %s; want %s, %s", got.StartTimeUnixNano, got.EndTimeUnixNano, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestToOTLP_StringSliceAttribute(t *testing.T) {
+	span := &Span{
+		TraceID: "t", SpanID: "s", ServiceName: "api",
+		Attributes: map[string]any{"llm.stop_sequences": []string{"a", "b"}},
+	}
+	attrs := ToOTLP([]*Span{span}).ResourceSpans[0].ScopeSpans[0].Spans[0].Attributes
+	got, err := json.Marshal(attrs)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	assertJSONEqual(t, got, []byte(`[{
+		"key": "llm.stop_sequences",
+		"value": {"arrayValue": {"values": [{"stringValue": "a"}, {"stringValue": "b"}]}}
+	}]`))
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+var spanPool = sync.Pool{
+	New: func() any {
+		return newSpan("", "", "")
+	},
+}
+
+// AcquireSpan returns a blank span from a shared pool: no IDs or start time,
+// Kind Internal, Sampled, and an empty Attributes map. It is meant for hot paths
+// that fill fields directly; hand it back with ReleaseSpan once exported.
+func AcquireSpan() *Span {
+	return spanPool.Get().(*Span)
+}
+
+// ReleaseSpan resets span and returns it to the pool. The caller must not touch
+// span, or anything still referencing it such as a SpanStore, afterwards.
+func ReleaseSpan(span *Span) {
+	if span == nil {
+		return
+	}
+	span.reset()
+	spanPool.Put(span)
+}
+
+// reset clears every field but keeps the Attributes map and the Events and
+// Links backing arrays so reuse does not allocate them again.
+func (s *Span) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.watchdog != nil {
+		s.watchdog.Stop()
+		s.watchdog = nil
+	}
+	s.watchdogGen++
+
+	s.TraceID = ""
+	s.SpanID = ""
+	s.ParentSpanID = ""
+	s.ServiceName = ""
+	s.OperationName = ""
+	s.ModelName = ""
+	s.Kind = SpanKindInternal
+	s.StartTimeUnixNano = 0
+	s.DurationNanos = 0
+	s.Status = StatusUnset
+	s.StatusDescription = ""
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]any)
+	}
+	clear(s.Attributes)
+	clear(s.Events)
+	s.Events = s.Events[:0]
+	clear(s.Links)
+	s.Links = s.Links[:0]
+	s.Resource = nil
+	s.Sampled = true
+
+	s.clock = nil
+	s.ids = nil
+	s.sampler = nil
+	s.ended = false
+	s.durationSet = false
+	s.start = time.Time{}
+	s.limits = nil
+}
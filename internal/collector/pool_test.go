@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReleaseSpan_ClearsPriorUse(t *testing.T) {
+	span := AcquireSpan()
+	span.TraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	span.SpanID = "00f067aa0ba902b7"
+	span.ServiceName = "api"
+	span.OperationName = "predict"
+	span.StartTimeUnixNano = 1
+	_ = span.SetAttribute("prompt", "secret")
+	span.AddEvent("first_token", map[string]any{"i": 1})
+	span.AddLink("0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331", nil)
+	span.RecordError(errors.New("boom"))
+	span.Resource = Resource{"host.name": "gpu-0"}
+	_ = span.End()
+	ReleaseSpan(span)
+
+	// The pool may or may not hand back the same object; reset it directly as
+	// well so the assertions below always cover the reset logic.
+	reused := AcquireSpan()
+	for _, s := range []*Span{reused, span} {
+		if s.TraceID != "" || s.SpanID != "" || s.ServiceName != "" || s.OperationName != "" {
+			t.Fatalf("identity fields survived release: %+v", s)
+		}
+		if s.StartTimeUnixNano != 0 || s.DurationNanos != 0 || s.Ended() {
+			t.Fatalf("timing survived release: start=%d duration=%d ended=%v", s.StartTimeUnixNano, s.DurationNanos, s.Ended())
+		}
+		if s.Status != StatusUnset || s.StatusDescription != "" {
+			t.Fatalf("status survived release: %v %q", s.Status, s.StatusDescription)
+		}
+		if len(s.Attributes) != 0 || len(s.Events) != 0 || len(s.Links) != 0 || s.Resource != nil {
+			t.Fatalf("collections survived release: attrs=%v events=%v links=%v resource=%v",
+				s.Attributes, s.Events, s.Links, s.Resource)
+		}
+		if s.Attributes == nil || !s.Sampled || s.Kind != SpanKindInternal {
+			t.Fatalf("released span not in AcquireSpan's initial state: %+v", s)
+		}
+	}
+	ReleaseSpan(reused)
+}
+
+func BenchmarkNewSpan(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		span, _ := NewSpan("api", "predict", "gpt-4o-mini")
+		_ = span.SetAttribute("llm.usage.prompt_tokens", int64(42))
+		_ = span.End()
+	}
+}
+
+func BenchmarkAcquireSpan(b *testing.B) {
+	ids := &SequentialIDGenerator{}
+	b.ReportAllocs()
+	for b.Loop() {
+		span := AcquireSpan()
+		span.TraceID = ids.NewTraceID()
+		span.SpanID = ids.NewSpanID()
+		span.ServiceName = "api"
+		span.OperationName = "predict"
+		span.ModelName = "gpt-4o-mini"
+		span.StartTimeUnixNano = span.now().UnixNano()
+		_ = span.SetAttribute("llm.usage.prompt_tokens", int64(42))
+		_ = span.End()
+		ReleaseSpan(span)
+	}
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Query returns every stored span for which pred is true, grouped by trace ID
+// in ascending order and in insertion order within a trace. pred must not call
+// back into the store.
+func (s *SpanStore) Query(pred func(*Span) bool) []*Span {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	traceIDs := make([]string, 0, len(s.traces))
+	for traceID := range s.traces {
+		traceIDs = append(traceIDs, traceID)
+	}
+	sort.Strings(traceIDs)
+
+	var out []*Span
+	for _, traceID := range traceIDs {
+		for _, span := range s.traces[traceID] {
+			if pred(span) {
+				out = append(out, span)
+			}
+		}
+	}
+	return out
+}
+
+// SpanFilter is a structured Query predicate. Zero-valued fields match
+// anything; all set fields must match.
+type SpanFilter struct {
+	ServiceName   string
+	OperationName string
+	ModelName     string
+	MinDuration   time.Duration
+	// Statuses matches spans with any of the listed status codes.
+	Statuses []StatusCode
+	// Attributes matches spans carrying every key with an equal value.
+	Attributes map[string]any
+}
+
+func (f SpanFilter) Match(span *Span) bool {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	if f.ServiceName != "" && span.ServiceName != f.ServiceName {
+		return false
+	}
+	if f.OperationName != "" && span.OperationName != f.OperationName {
+		return false
+	}
+	if f.ModelName != "" && span.ModelName != f.ModelName {
+		return false
+	}
+	if f.MinDuration > 0 && time.Duration(span.DurationNanos) < f.MinDuration {
+		return false
+	}
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, code := range f.Statuses {
+			if span.Status == code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, want := range f.Attributes {
+		got, ok := span.Attributes[key]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SpanStore) QueryFilter(f SpanFilter) []*Span {
+	return s.Query(f.Match)
+}
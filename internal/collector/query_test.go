@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func queryTestStore() *SpanStore {
+	s := NewSpanStore()
+	for _, span := range []*Span{
+		{TraceID: "t1", SpanID: "a", ModelName: "gpt-4o", DurationNanos: int64(3 * time.Second), Status: StatusError,
+			Attributes: map[string]any{"gpu.id": "gpu-0"}},
+		{TraceID: "t1", SpanID: "b", ModelName: "gpt-4o", DurationNanos: int64(time.Second), Status: StatusError},
+		{TraceID: "t2", SpanID: "c", ModelName: "gpt-4o", DurationNanos: int64(5 * time.Second), Status: StatusOK,
+			Attributes: map[string]any{"gpu.id": "gpu-1"}},
+		{TraceID: "t0", SpanID: "d", ModelName: "llama", DurationNanos: int64(4 * time.Second), Status: StatusError,
+			Attributes: map[string]any{"gpu.id": "gpu-0"}},
+	} {
+		s.Add(span)
+	}
+	return s
+}
+
+func spanIDs(spans []*Span) []string {
+	ids := make([]string, len(spans))
+	for i, span := range spans {
+		ids[i] = span.SpanID
+	}
+	return ids
+}
+
+func TestSpanStore_QueryFilter(t *testing.T) {
+	s := queryTestStore()
+
+	tests := []struct {
+		name   string
+		filter SpanFilter
+		want   []string
+	}{
+		{
+			name:   "slow errors",
+			filter: SpanFilter{MinDuration: 2 * time.Second, Statuses: []StatusCode{StatusError}},
+			want:   []string{"d", "a"},
+		},
+		{
+			name:   "slow errors for one model",
+			filter: SpanFilter{ModelName: "gpt-4o", MinDuration: 2 * time.Second, Statuses: []StatusCode{StatusError}},
+			want:   []string{"a"},
+		},
+		{
+			name:   "attribute equality",
+			filter: SpanFilter{Attributes: map[string]any{"gpu.id": "gpu-0"}},
+			want:   []string{"d", "a"},
+		},
+		{
+			name:   "attribute and status",
+			filter: SpanFilter{Attributes: map[string]any{"gpu.id": "gpu-1"}, Statuses: []StatusCode{StatusError}},
+			want:   nil,
+		},
+		{
+			name:   "empty filter matches all",
+			filter: SpanFilter{},
+			want:   []string{"d", "a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		got := spanIDs(s.QueryFilter(tt.filter))
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSpanStore_QueryPredicate(t *testing.T) {
+	s := queryTestStore()
+	got := s.Query(func(span *Span) bool { return span.TraceID == "t2" })
+	if len(got) != 1 || got[0].SpanID != "c" {
+		t.Fatalf("Query = %v, want [c]", spanIDs(got))
+	}
+}
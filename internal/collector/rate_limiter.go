@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket per ServiceName. Each service may burst up to
+// burst spans and then refills at ratePerSecond. A nil *RateLimiter allows
+// everything, which is the receivers' default.
+type RateLimiter struct {
+	ratePerSecond float64
+	burst         float64
+	clock         Clock
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &RateLimiter{
+		ratePerSecond: ratePerSecond,
+		burst:         float64(burst),
+		clock:         realClock{},
+		buckets:       make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes one token from service's bucket, reporting false if it is empty.
+func (l *RateLimiter) Allow(service string) bool {
+	if l == nil {
+		return true
+	}
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.refillLocked(service, now)
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+func (l *RateLimiter) refillLocked(service string, now time.Time) *tokenBucket {
+	bucket, ok := l.buckets[service]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[service] = bucket
+	}
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = min(l.burst, bucket.tokens+elapsed.Seconds()*l.ratePerSecond)
+		bucket.last = now
+	}
+	return bucket
+}
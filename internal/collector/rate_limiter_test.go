@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_PerServiceBuckets(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	limiter := NewRateLimiter(1, 3)
+	limiter.clock = clock
+
+	for i := range 3 {
+		if !limiter.Allow("noisy") {
+			t.Fatalf("Allow(noisy) #%d = false within burst", i)
+		}
+	}
+	if limiter.Allow("noisy") {
+		t.Fatalf("Allow(noisy) = true past burst")
+	}
+	if !limiter.Allow("quiet") {
+		t.Fatalf("Allow(quiet) = false, want true")
+	}
+
+	clock.Advance(time.Second)
+	if !limiter.Allow("noisy") {
+		t.Fatalf("Allow(noisy) = false after refill")
+	}
+	if limiter.Allow("noisy") {
+		t.Fatalf("Allow(noisy) = true, want only one token refilled")
+	}
+}
+
+func TestRateLimiter_NilAllowsAll(t *testing.T) {
+	var limiter *RateLimiter
+	if !limiter.Allow("any") {
+		t.Fatalf("nil limiter Allow = false, want true")
+	}
+}
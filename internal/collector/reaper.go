@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EvictIdle removes every trace that has not gained a span for at least idle
+// and returns them, oldest first. The store treats such traces as complete.
+// Traces that only ever had trace attributes are removed but not returned.
+func (s *SpanStore) EvictIdle(idle time.Duration) [][]*Span {
+	evicted := s.evictIdle(idle)
+	traces := make([][]*Span, 0, len(evicted))
+	for _, trace := range evicted {
+		traces = append(traces, trace.spans)
+	}
+	return traces
+}
+
+type evictedTrace struct {
+	spans []*Span
+	attrs map[string]any
+}
+
+func (s *SpanStore) evictIdle(idle time.Duration) []evictedTrace {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	for traceID, updated := range s.lastUpdated {
+		if now.Sub(updated) >= idle {
+			expired = append(expired, traceID)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return s.lastUpdated[expired[i]].Before(s.lastUpdated[expired[j]])
+	})
+
+	traces := make([]evictedTrace, 0, len(expired))
+	for _, traceID := range expired {
+		if spans := s.traces[traceID]; len(spans) > 0 {
+			traces = append(traces, evictedTrace{spans: spans, attrs: s.traceAttrs[traceID]})
+		}
+		s.deleteLocked(traceID)
+	}
+	return traces
+}
+
+type ReaperOption func(*Reaper)
+
+// WithFoldTraceAttributes makes the reaper copy each trace's trace attributes
+// onto its root span, via FoldTraceAttributes, before exporting it.
+func WithFoldTraceAttributes() ReaperOption {
+	return func(r *Reaper) {
+		r.foldAttrs = true
+	}
+}
+
+// Reaper bounds SpanStore memory: every interval it evicts traces idle for at
+// least idleTimeout and exports each one as a batch.
+type Reaper struct {
+	store       *SpanStore
+	exporter    Exporter
+	idleTimeout time.Duration
+	foldAttrs   bool
+
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewReaper(store *SpanStore, exporter Exporter, idleTimeout, interval time.Duration, opts ...ReaperOption) *Reaper {
+	if interval <= 0 {
+		interval = idleTimeout
+	}
+	r := &Reaper{
+		store:       store,
+		exporter:    exporter,
+		idleTimeout: idleTimeout,
+		stopCh:      make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	go r.loop(interval)
+	return r
+}
+
+func (r *Reaper) loop(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Reap(context.Background()); err != nil {
+				log.Printf("span store reaper export error: %v", err)
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// Reap runs one eviction pass immediately. Traces are removed from the store
+// before export, so a failed export drops them; the errors are joined.
+func (r *Reaper) Reap(ctx context.Context) error {
+	var errs []error
+	for _, trace := range r.store.evictIdle(r.idleTimeout) {
+		spans := trace.spans
+		if r.foldAttrs {
+			spans = FoldTraceAttributes(spans, trace.attrs)
+		}
+		if err := r.exporter.Export(ctx, spans); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stop halts the background loop. It does not flush remaining traces.
+func (r *Reaper) Stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	<-r.done
+}
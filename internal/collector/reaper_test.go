@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReaper_ExportsAndEvictsIdleTraces(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	spanStore := NewSpanStore(WithStoreClock(clock))
+	exporter := &recordingExporter{}
+	reaper := NewReaper(spanStore, exporter, time.Minute, time.Hour)
+	t.Cleanup(reaper.Stop)
+
+	spanStore.Add(&Span{TraceID: "old", SpanID: "a"})
+	spanStore.Add(&Span{TraceID: "old", SpanID: "b", ParentSpanID: "a"})
+	clock.Advance(30 * time.Second)
+	spanStore.Add(&Span{TraceID: "fresh", SpanID: "c"})
+
+	clock.Advance(40 * time.Second)
+	if err := reaper.Reap(context.Background()); err != nil {
+		t.Fatalf("Reap returned error: %v", err)
+	}
+
+	batches := exporter.Batches()
+	if len(batches) != 1 || len(batches[0]) != 2 || batches[0][0].TraceID != "old" {
+		t.Fatalf("exported %v, want the two spans of trace old", batches)
+	}
+	if _, ok := spanStore.GetTrace("old"); ok {
+		t.Fatalf("idle trace still in store")
+	}
+	if _, ok := spanStore.GetTrace("fresh"); !ok {
+		t.Fatalf("active trace was evicted")
+	}
+
+	clock.Advance(time.Minute)
+	_ = reaper.Reap(context.Background())
+	if exporter.SpanCount() != 3 {
+		t.Fatalf("exported %d spans, want 3 after second pass", exporter.SpanCount())
+	}
+}
+
+func TestReaper_NewSpanResetsIdleTimer(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	spanStore := NewSpanStore(WithStoreClock(clock))
+
+	spanStore.Add(&Span{TraceID: "t", SpanID: "a"})
+	clock.Advance(50 * time.Second)
+	spanStore.Add(&Span{TraceID: "t", SpanID: "b"})
+	clock.Advance(50 * time.Second)
+
+	if got := spanStore.EvictIdle(time.Minute); len(got) != 0 {
+		t.Fatalf("evicted %d traces, want 0", len(got))
+	}
+}
+
+func TestReaper_BackgroundLoop(t *testing.T) {
+	spanStore := NewSpanStore()
+	exporter := &recordingExporter{}
+	spanStore.Add(&Span{TraceID: "t", SpanID: "a"})
+
+	reaper := NewReaper(spanStore, exporter, time.Nanosecond, 5*time.Millisecond)
+	defer reaper.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for exporter.SpanCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("background reaper never exported the idle trace")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReaper_MergedUpdateResetsIdleTimer(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	spanStore := NewSpanStore(WithStoreClock(clock), WithDedupMode(DedupMerge))
+
+	spanStore.Add(&Span{TraceID: "t", SpanID: "a"})
+	clock.Advance(50 * time.Second)
+	spanStore.Add(&Span{TraceID: "t", SpanID: "a", DurationNanos: 250})
+	clock.Advance(50 * time.Second)
+
+	if got := spanStore.EvictIdle(time.Minute); len(got) != 0 {
+		t.Fatalf("evicted %d traces, want 0 after a merged update", len(got))
+	}
+}
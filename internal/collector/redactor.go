@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"context"
+	"math"
+)
+
+const RedactedValue = "[REDACTED]"
+
+type RedactMode int
+
+const (
+	// RedactRemove deletes matching attributes.
+	RedactRemove RedactMode = iota
+	// RedactMask keeps the key but replaces the value with RedactedValue.
+	RedactMask
+)
+
+// Redactor strips or masks sensitive attributes (prompts, user IDs, ...) from
+// spans and their events before they leave the process.
+type Redactor struct {
+	mode  RedactMode
+	match func(key string) bool
+
+	// keep maps attribute keys to the fraction of spans allowed to carry them.
+	keep map[string]float64
+}
+
+func NewRedactor(mode RedactMode, keys ...string) *Redactor {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return NewRedactorFunc(mode, func(key string) bool {
+		_, ok := set[key]
+		return ok
+	})
+}
+
+func NewRedactorFunc(mode RedactMode, match func(key string) bool) *Redactor {
+	if match == nil {
+		match = func(string) bool { return false }
+	}
+	return &Redactor{
+		mode:  mode,
+		match: match,
+	}
+}
+
+// KeepFraction keeps attribute key on roughly rate (0..1) of spans and removes
+// it from the rest, for heavy fields like prompts that are useful on a sample.
+// The decision depends only on the span ID, so it is stable across retries and
+// exporters. It returns r for chaining.
+func (r *Redactor) KeepFraction(key string, rate float64) *Redactor {
+	if r.keep == nil {
+		r.keep = make(map[string]float64)
+	}
+	r.keep[key] = min(max(rate, 0), 1)
+	return r
+}
+
+// Redact rewrites span in place.
+func (r *Redactor) Redact(span *Span) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	r.redactAttributes(span.Attributes)
+	for i := range span.Events {
+		r.redactAttributes(span.Events[i].Attributes)
+	}
+	r.sampleAttributes(span.SpanID, span.Attributes)
+}
+
+func (r *Redactor) sampleAttributes(spanID string, attrs map[string]any) {
+	if len(r.keep) == 0 || len(attrs) == 0 {
+		return
+	}
+	value := traceIDValue(spanID)
+	for key, rate := range r.keep {
+		if rate >= 1 {
+			continue
+		}
+		if rate <= 0 || value >= uint64(rate*math.MaxUint64) {
+			delete(attrs, key)
+		}
+	}
+}
+
+func (r *Redactor) redactAttributes(attrs map[string]any) {
+	for key := range attrs {
+		if !r.match(key) {
+			continue
+		}
+		if r.mode == RedactMask {
+			attrs[key] = RedactedValue
+		} else {
+			delete(attrs, key)
+		}
+	}
+}
+
+// Wrap returns an Exporter that redacts clones of each span before handing them
+// to next, leaving the caller's spans untouched.
+func (r *Redactor) Wrap(next Exporter) Exporter {
+	return ExporterFunc(func(ctx context.Context, spans []*Span) error {
+		if len(spans) == 0 {
+			return nil
+		}
+		redacted := make([]*Span, 0, len(spans))
+		for _, span := range spans {
+			if span == nil {
+				continue
+			}
+			clone := span.Clone()
+			r.Redact(clone)
+			redacted = append(redacted, clone)
+		}
+		return next.Export(ctx, redacted)
+	})
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func newRedactionTestSpan() *Span {
+	span := &Span{Attributes: map[string]any{
+		"llm.prompt": "my SSN is 123",
+		"user.id":    "u-42",
+		"gpu.id":     "gpu-0",
+	}}
+	span.AddEvent("prompt received", map[string]any{"llm.prompt": "my SSN is 123", "bytes": int64(13)})
+	return span
+}
+
+func TestRedactor_RemoveMode(t *testing.T) {
+	span := newRedactionTestSpan()
+	NewRedactor(RedactRemove, "llm.prompt", "user.id").Redact(span)
+
+	if _, ok := span.Attributes["llm.prompt"]; ok {
+		t.Fatalf("llm.prompt not removed")
+	}
+	if _, ok := span.Attributes["user.id"]; ok {
+		t.Fatalf("user.id not removed")
+	}
+	if span.Attributes["gpu.id"] != "gpu-0" {
+		t.Fatalf("unrelated attribute changed: %v", span.Attributes["gpu.id"])
+	}
+	if _, ok := span.Events[0].Attributes["llm.prompt"]; ok {
+		t.Fatalf("event llm.prompt not removed")
+	}
+	if span.Events[0].Attributes["bytes"] != int64(13) {
+		t.Fatalf("unrelated event attribute changed")
+	}
+}
+
+func TestRedactor_MaskMode(t *testing.T) {
+	span := newRedactionTestSpan()
+	NewRedactorFunc(RedactMask, func(key string) bool { return strings.HasPrefix(key, "llm.") }).Redact(span)
+
+	if span.Attributes["llm.prompt"] != RedactedValue {
+		t.Fatalf("llm.prompt = %v, want %q", span.Attributes["llm.prompt"], RedactedValue)
+	}
+	if span.Events[0].Attributes["llm.prompt"] != RedactedValue {
+		t.Fatalf("event llm.prompt = %v, want %q", span.Events[0].Attributes["llm.prompt"], RedactedValue)
+	}
+	if span.Attributes["user.id"] != "u-42" {
+		t.Fatalf("user.id changed: %v", span.Attributes["user.id"])
+	}
+}
+
+func TestRedactor_MissingKeysAreNoop(t *testing.T) {
+	span := newRedactionTestSpan()
+	NewRedactor(RedactMask, "does.not.exist").Redact(span)
+
+	if len(span.Attributes) != 3 {
+		t.Fatalf("attribute count = %d, want 3", len(span.Attributes))
+	}
+	if _, ok := span.Attributes["does.not.exist"]; ok {
+		t.Fatalf("mask mode must not add missing keys")
+	}
+}
+
+func TestRedactor_WrapLeavesOriginalUntouched(t *testing.T) {
+	exporter := &recordingExporter{}
+	span := newRedactionTestSpan()
+
+	wrapped := NewRedactor(RedactRemove, "llm.prompt").Wrap(exporter)
+	if err := wrapped.Export(context.Background(), []*Span{span}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	exported := exporter.Batches()[0][0]
+	if _, ok := exported.Attributes["llm.prompt"]; ok {
+		t.Fatalf("exporter saw unredacted prompt")
+	}
+	if span.Attributes["llm.prompt"] != "my SSN is 123" {
+		t.Fatalf("original span was modified")
+	}
+}
+
+func TestRedactor_KeepFraction(t *testing.T) {
+	sampleSpan := func(id string) *Span {
+		return &Span{SpanID: id, Attributes: map[string]any{"llm.prompt": "long prompt", "gpu.id": "gpu-0"}}
+	}
+	ids := make([]string, 200)
+	for i := range ids {
+		ids[i] = GenerateSpanID()
+	}
+
+	never := NewRedactor(RedactRemove).KeepFraction("llm.prompt", 0)
+	always := NewRedactor(RedactRemove).KeepFraction("llm.prompt", 1)
+	half := NewRedactor(RedactRemove).KeepFraction("llm.prompt", 0.5)
+
+	kept := 0
+	for _, id := range ids {
+		span := sampleSpan(id)
+		never.Redact(span)
+		if _, ok := span.Attributes["llm.prompt"]; ok {
+			t.Fatalf("rate 0 kept attribute on span %s", id)
+		}
+		if span.Attributes["gpu.id"] != "gpu-0" {
+			t.Fatalf("unrelated attribute removed")
+		}
+
+		span = sampleSpan(id)
+		always.Redact(span)
+		if _, ok := span.Attributes["llm.prompt"]; !ok {
+			t.Fatalf("rate 1 removed attribute on span %s", id)
+		}
+
+		first, second := sampleSpan(id), sampleSpan(id)
+		half.Redact(first)
+		half.Redact(second)
+		_, keptFirst := first.Attributes["llm.prompt"]
+		_, keptSecond := second.Attributes["llm.prompt"]
+		if keptFirst != keptSecond {
+			t.Fatalf("decision for span %s not stable", id)
+		}
+		if keptFirst {
+			kept++
+		}
+	}
+	if kept < 60 || kept > 140 {
+		t.Fatalf("rate 0.5 kept %d of %d, want roughly half", kept, len(ids))
+	}
+}
@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+const replayBatchSize = 256
+
+// ReplayStats reports what Replay did. BadLines holds the 1-based line numbers
+// that could not be decoded or failed validation.
+type ReplayStats struct {
+	Exported int
+	BadLines []int
+}
+
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	rebase   bool
+	rebaseTo time.Time
+}
+
+// WithRebaseTime shifts every span so the first replayed span starts at start,
+// keeping the original gaps between spans. Without it timestamps are preserved.
+func WithRebaseTime(start time.Time) ReplayOption {
+	return func(c *replayConfig) {
+		c.rebase = true
+		c.rebaseTo = start
+	}
+}
+
+// Replay reads newline-delimited span JSON, as written by FileExporter, and
+// exports it in batches. Malformed or invalid lines are skipped and recorded in
+// the returned stats; only read and export errors stop the replay.
+func Replay(ctx context.Context, r io.Reader, exporter Exporter, opts ...ReplayOption) (ReplayStats, error) {
+	var cfg replayConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		stats  ReplayStats
+		batch  []*Span
+		offset int64
+		based  bool
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := exporter.Export(ctx, batch); err != nil {
+			return err
+		}
+		stats.Exported += len(batch)
+		batch = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		span := &Span{}
+		if err := json.Unmarshal(line, span); err != nil || span.Validate() != nil {
+			stats.BadLines = append(stats.BadLines, lineNo)
+			continue
+		}
+		if cfg.rebase {
+			if !based {
+				offset = cfg.rebaseTo.UnixNano() - span.StartTimeUnixNano
+				based = true
+			}
+			span.StartTimeUnixNano += offset
+			for i := range span.Events {
+				span.Events[i].TimeUnixNano += offset
+			}
+		}
+
+		batch = append(batch, span)
+		if len(batch) >= replayBatchSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	return stats, flush()
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const replayFixture = `{"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","service_name":"api","operation_name":"predict","start_time_unix_nano":1000,"duration_nanos":50}
+not json
+{"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"b7ad6b7169203331","parent_span_id":"00f067aa0ba902b7","service_name":"api","operation_name":"encode","start_time_unix_nano":1010,"duration_nanos":5,"events":[{"name":"first_token","time_unix_nano":1012}]}
+
+{"trace_id":"","span_id":"c7ad6b7169203331","start_time_unix_nano":1}
+`
+
+func TestReplay_ExportsValidSpansAndCountsBadLines(t *testing.T) {
+	exporter := &recordingExporter{}
+
+	stats, err := Replay(context.Background(), strings.NewReader(replayFixture), exporter)
+	if err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+	if stats.Exported != 2 {
+		t.Fatalf("Exported = %d, want 2", stats.Exported)
+	}
+	if want := []int{2, 5}; !reflect.DeepEqual(stats.BadLines, want) {
+		t.Fatalf("BadLines = %v, want %v", stats.BadLines, want)
+	}
+
+	batches := exporter.Batches()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("batches = %v, want one batch of 2", batches)
+	}
+	if got := batches[0][1]; got.OperationName != "encode" || got.StartTimeUnixNano != 1010 {
+		t.Fatalf("second span = %+v", got)
+	}
+}
+
+func TestReplay_RebaseTime(t *testing.T) {
+	exporter := &recordingExporter{}
+	start := time.Unix(1700000000, 0)
+
+	if _, err := Replay(context.Background(), strings.NewReader(replayFixture), exporter, WithRebaseTime(start)); err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+
+	spans := exporter.Batches()[0]
+	if spans[0].StartTimeUnixNano != start.UnixNano() {
+		t.Fatalf("first start = %d, want %d", spans[0].StartTimeUnixNano, start.UnixNano())
+	}
+	if got := spans[1].StartTimeUnixNano - spans[0].StartTimeUnixNano; got != 10 {
+		t.Fatalf("gap between spans = %d, want 10", got)
+	}
+	if got := spans[1].Events[0].TimeUnixNano; got != start.UnixNano()+12 {
+		t.Fatalf("event time = %d, want %d", got, start.UnixNano()+12)
+	}
+}
+
+func TestReplay_RoundTripsFileExporterOutput(t *testing.T) {
+	var buf bytes.Buffer
+	span := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", ServiceName: "api", StartTimeUnixNano: 5}
+	if err := NewStdoutExporter(&buf).Export(context.Background(), []*Span{span}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	exporter := &recordingExporter{}
+	stats, err := Replay(context.Background(), &buf, exporter)
+	if err != nil || stats.Exported != 1 {
+		t.Fatalf("Replay = %+v, %v; want 1 exported", stats, err)
+	}
+}
+
+func TestReplay_ExportErrorStops(t *testing.T) {
+	want := errors.New("downstream unavailable")
+	exporter := ExporterFunc(func(context.Context, []*Span) error { return want })
+
+	if _, err := Replay(context.Background(), strings.NewReader(replayFixture), exporter); !errors.Is(err, want) {
+		t.Fatalf("Replay error = %v, want %v", err, want)
+	}
+}
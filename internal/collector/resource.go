@@ -0,0 +1,16 @@
+package collector
+
+// Resource holds attributes that describe the process emitting spans (host.name,
+// k8s.pod.name, service.version, ...). Spans share one Resource by reference
+// instead of each carrying a copy of the keys.
+type Resource map[string]any
+
+type ProcessorOption func(*BatchProcessor)
+
+// WithResource attaches resource to every span the processor receives that does
+// not already have one.
+func WithResource(resource Resource) ProcessorOption {
+	return func(p *BatchProcessor) {
+		p.resource = resource
+	}
+}
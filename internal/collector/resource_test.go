@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBatchProcessor_WithResourceSharesOneResource(t *testing.T) {
+	exporter := &recordingExporter{}
+	resource := Resource{"host.name": "gpu-node-7", "service.version": "1.4.2"}
+	p := NewBatchProcessor(exporter, 100, time.Hour, WithResource(resource))
+
+	own := Resource{"host.name": "edge-1"}
+	spans := []*Span{
+		{SpanID: "a", ServiceName: "inference-api", Sampled: true},
+		{SpanID: "b", ServiceName: "inference-api", Sampled: true},
+		{SpanID: "c", ServiceName: "tokenizer", Resource: own, Sampled: true},
+	}
+	for _, span := range spans {
+		if err := p.Add(span); err != nil {
+			t.Fatalf("Add returned error: %v", err)
+		}
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	if spans[0].Resource["host.name"] != "gpu-node-7" || spans[1].Resource["service.version"] != "1.4.2" {
+		t.Fatalf("expected processor resource on spans a and b")
+	}
+	if spans[2].Resource["host.name"] != "edge-1" {
+		t.Fatalf("span with its own resource was overwritten: %v", spans[2].Resource)
+	}
+}
+
+func TestToOTLP_ResourceAttributesOncePerService(t *testing.T) {
+	resource := Resource{"host.name": "gpu-node-7"}
+	spans := []*Span{
+		{SpanID: "a", ServiceName: "inference-api", Resource: resource},
+		{SpanID: "b", ServiceName: "inference-api", Resource: resource},
+		{SpanID: "c", ServiceName: "tokenizer", Resource: resource},
+	}
+
+	out := ToOTLP(spans)
+	if got, want := len(out.ResourceSpans), 2; got != want {
+		t.Fatalf("resource block count = %d, want %d", got, want)
+	}
+
+	for _, rs := range out.ResourceSpans {
+		hostNames := 0
+		for _, kv := range rs.Resource.Attributes {
+			if kv.Key == "host.name" {
+				hostNames++
+			}
+		}
+		if hostNames != 1 {
+			t.Fatalf("host.name appears %d times in resource block, want 1", hostNames)
+		}
+		for _, span := range rs.ScopeSpans[0].Spans {
+			for _, kv := range span.Attributes {
+				if kv.Key == "host.name" {
+					t.Fatalf("resource attribute duplicated onto span %s", span.SpanID)
+				}
+			}
+		}
+	}
+	if got := len(out.ResourceSpans[0].ScopeSpans[0].Spans); got != 2 {
+		t.Fatalf("inference-api span count = %d, want 2", got)
+	}
+}
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultRingCapacity = 1024
+
+// RingExporter keeps the last N exported spans in memory for inspection, for
+// example behind a debug endpoint. Once full, each new span overwrites the
+// oldest. It stores clones, so later changes to an exported span are not seen.
+type RingExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+	next  int
+	full  bool
+}
+
+// NewRingExporter keeps at most capacity spans; capacity <= 0 selects 1024.
+func NewRingExporter(capacity int) *RingExporter {
+	if capacity <= 0 {
+		capacity = defaultRingCapacity
+	}
+	return &RingExporter{spans: make([]*Span, capacity)}
+}
+
+func (r *RingExporter) Export(ctx context.Context, spans []*Span) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	clones := make([]*Span, 0, len(spans))
+	for _, span := range spans {
+		if span != nil {
+			clones = append(clones, span.Clone())
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, span := range clones {
+		r.spans[r.next] = span
+		r.next = (r.next + 1) % len(r.spans)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return nil
+}
+
+// Recent returns the retained spans, newest first.
+func (r *RingExporter) Recent() []*Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.spans)
+	}
+	out := make([]*Span, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.spans[(r.next-i+len(r.spans))%len(r.spans)])
+	}
+	return out
+}
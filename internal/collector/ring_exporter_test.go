@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func ringSpans(from, to int) []*Span {
+	var spans []*Span
+	for i := from; i <= to; i++ {
+		spans = append(spans, &Span{SpanID: strconv.Itoa(i)})
+	}
+	return spans
+}
+
+func recentIDs(r *RingExporter) string {
+	var ids []string
+	for _, span := range r.Recent() {
+		ids = append(ids, span.SpanID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRingExporter_KeepsMostRecentNewestFirst(t *testing.T) {
+	ring := NewRingExporter(3)
+	if got := recentIDs(ring); got != "" {
+		t.Fatalf("empty ring Recent = %q, want none", got)
+	}
+
+	if err := ring.Export(context.Background(), ringSpans(1, 2)); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := recentIDs(ring); got != "2,1" {
+		t.Fatalf("Recent = %s, want 2,1", got)
+	}
+
+	if err := ring.Export(context.Background(), append(ringSpans(3, 4), nil, &Span{SpanID: "5"})); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := recentIDs(ring); got != "5,4,3" {
+		t.Fatalf("Recent = %s, want 5,4,3", got)
+	}
+}
+
+func TestRingExporter_StoresCopies(t *testing.T) {
+	ring := NewRingExporter(2)
+	span := &Span{SpanID: "a", Attributes: map[string]any{"k": "v"}}
+	if err := ring.Export(context.Background(), []*Span{span}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	span.Attributes["k"] = "changed"
+
+	if got := ring.Recent()[0].Attributes["k"]; got != "v" {
+		t.Fatalf("retained attribute = %v, want v", got)
+	}
+}
+
+func TestRingExporter_Concurrent(t *testing.T) {
+	ring := NewRingExporter(16)
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				_ = ring.Export(context.Background(), ringSpans(w*100+i, w*100+i))
+				_ = ring.Recent()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(ring.Recent()); got != 16 {
+		t.Fatalf("len(Recent()) = %d, want 16", got)
+	}
+}
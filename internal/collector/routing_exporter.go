@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+)
+
+type RoutingOption func(*RoutingExporter)
+
+// WithDefaultRoute sends spans without a matching route to exporter instead of
+// dropping them.
+func WithDefaultRoute(exporter Exporter) RoutingOption {
+	return func(r *RoutingExporter) {
+		r.fallback = exporter
+	}
+}
+
+// RoutingExporter splits each batch by a route key, such as a tenant ID, and
+// exports each part to that route's exporter. Spans whose key has no route go
+// to the default route, or are dropped and counted when there is none.
+type RoutingExporter struct {
+	route    func(*Span) string
+	routes   map[string]Exporter
+	fallback Exporter
+	dropped  atomic.Int64
+}
+
+func NewRoutingExporter(route func(*Span) string, routes map[string]Exporter, opts ...RoutingOption) *RoutingExporter {
+	r := &RoutingExporter{route: route, routes: routes}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RouteByAttribute returns a route function keyed on a string attribute. Spans
+// without it, or with a non-string value, get the empty key.
+func RouteByAttribute(key string) func(*Span) string {
+	return func(span *Span) string {
+		value, _ := span.GetString(key)
+		return value
+	}
+}
+
+// Export calls each route once with its spans, in key order, then the default
+// route. Every route is tried even if one fails; the errors are joined.
+func (r *RoutingExporter) Export(ctx context.Context, spans []*Span) error {
+	batches := make(map[string][]*Span)
+	var unmatched []*Span
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		key := r.route(span)
+		if _, ok := r.routes[key]; ok {
+			batches[key] = append(batches[key], span)
+		} else {
+			unmatched = append(unmatched, span)
+		}
+	}
+
+	keys := make([]string, 0, len(batches))
+	for key := range batches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := r.routes[key].Export(ctx, batches[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(unmatched) > 0 {
+		if r.fallback == nil {
+			r.dropped.Add(int64(len(unmatched)))
+		} else if err := r.fallback.Export(ctx, unmatched); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DroppedSpans reports how many unmatched spans were dropped for lack of a
+// default route.
+func (r *RoutingExporter) DroppedSpans() int64 {
+	return r.dropped.Load()
+}
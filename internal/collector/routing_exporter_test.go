@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func tenantSpan(id, tenant string) *Span {
+	span := &Span{SpanID: id, Attributes: map[string]any{}}
+	if tenant != "" {
+		span.Attributes["tenant.id"] = tenant
+	}
+	return span
+}
+
+func TestRoutingExporter_RoutesByTenant(t *testing.T) {
+	acme, globex, fallback := &recordingExporter{}, &recordingExporter{}, &recordingExporter{}
+	router := NewRoutingExporter(RouteByAttribute("tenant.id"), map[string]Exporter{
+		"acme":   acme,
+		"globex": globex,
+	}, WithDefaultRoute(fallback))
+
+	spans := []*Span{
+		tenantSpan("a1", "acme"),
+		tenantSpan("g1", "globex"),
+		tenantSpan("a2", "acme"),
+		tenantSpan("u1", "initech"),
+		tenantSpan("n1", ""),
+		nil,
+	}
+	if err := router.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		exporter *recordingExporter
+		want     string
+	}{
+		{name: "acme", exporter: acme, want: "a1,a2"},
+		{name: "globex", exporter: globex, want: "g1"},
+		{name: "default", exporter: fallback, want: "u1,n1"},
+	}
+	for _, tc := range tests {
+		batches := tc.exporter.Batches()
+		if len(batches) != 1 {
+			t.Fatalf("%s got %d batches, want 1", tc.name, len(batches))
+		}
+		if got := strings.Join(spanIDs(batches[0]), ","); got != tc.want {
+			t.Fatalf("%s spans = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRoutingExporter_DropsUnmatchedWithoutDefault(t *testing.T) {
+	acme := &recordingExporter{}
+	router := NewRoutingExporter(RouteByAttribute("tenant.id"), map[string]Exporter{"acme": acme})
+
+	if err := router.Export(context.Background(), []*Span{tenantSpan("a1", "acme"), tenantSpan("u1", "initech")}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if got := acme.SpanCount(); got != 1 {
+		t.Fatalf("acme spans = %d, want 1", got)
+	}
+	if got := router.DroppedSpans(); got != 1 {
+		t.Fatalf("DroppedSpans = %d, want 1", got)
+	}
+}
+
+func TestRoutingExporter_JoinsRouteErrors(t *testing.T) {
+	errAcme := errors.New("acme down")
+	acme := &recordingExporter{err: errAcme}
+	globex := &recordingExporter{}
+	router := NewRoutingExporter(RouteByAttribute("tenant.id"), map[string]Exporter{"acme": acme, "globex": globex})
+
+	err := router.Export(context.Background(), []*Span{tenantSpan("a1", "acme"), tenantSpan("g1", "globex")})
+	if !errors.Is(err, errAcme) {
+		t.Fatalf("Export error = %v, want %v", err, errAcme)
+	}
+	if got := globex.SpanCount(); got != 1 {
+		t.Fatalf("globex spans = %d, want 1 despite acme failing", got)
+	}
+}
@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+	"math"
+)
+
+type Sampler interface {
+	ShouldSample(traceID string) bool
+}
+
+// WithSampler sets Span.Sampled from sampler's decision for the span's trace ID,
+// once IDs have been assigned. It has no effect on spans with a parent.
+func WithSampler(sampler Sampler) SpanOption {
+	return func(s *Span) {
+		s.sampler = sampler
+	}
+}
+
+// ProbabilitySampler keeps roughly rate of all traces. The decision is derived
+// from the trace ID alone, so every span of a trace gets the same answer no
+// matter which service asks.
+type ProbabilitySampler struct {
+	rate      float64
+	threshold uint64
+}
+
+func NewProbabilitySampler(rate float64) *ProbabilitySampler {
+	if rate < 0 {
+		rate = 0
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	return &ProbabilitySampler{
+		rate:      rate,
+		threshold: uint64(rate * math.MaxUint64),
+	}
+}
+
+func (s *ProbabilitySampler) ShouldSample(traceID string) bool {
+	if s.rate <= 0 {
+		return false
+	}
+	if s.rate >= 1 {
+		return true
+	}
+	return traceIDValue(traceID) < s.threshold
+}
+
+// traceIDValue reads the low 8 bytes of a hex trace ID, which are random for
+// W3C IDs. Non-hex IDs fall back to an FNV hash so they still sample stably.
+func traceIDValue(traceID string) uint64 {
+	if len(traceID) >= spanIDHexLen {
+		if raw, err := hex.DecodeString(traceID[len(traceID)-spanIDHexLen:]); err == nil {
+			return binary.BigEndian.Uint64(raw)
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(traceID))
+	return h.Sum64()
+}
@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProbabilitySampler_Extremes(t *testing.T) {
+	never := NewProbabilitySampler(0)
+	always := NewProbabilitySampler(1)
+
+	for range 200 {
+		traceID := GenerateTraceID()
+		if never.ShouldSample(traceID) {
+			t.Fatalf("rate 0 sampled trace %q", traceID)
+		}
+		if !always.ShouldSample(traceID) {
+			t.Fatalf("rate 1 dropped trace %q", traceID)
+		}
+	}
+}
+
+func TestProbabilitySampler_StableDecision(t *testing.T) {
+	sampler := NewProbabilitySampler(0.5)
+
+	for _, traceID := range []string{GenerateTraceID(), GenerateTraceID(), "not-a-hex-trace-id"} {
+		first := sampler.ShouldSample(traceID)
+		for range 20 {
+			if got := sampler.ShouldSample(traceID); got != first {
+				t.Fatalf("decision for %q flipped from %v to %v", traceID, first, got)
+			}
+		}
+	}
+}
+
+func TestProbabilitySampler_ApproximatesRate(t *testing.T) {
+	sampler := NewProbabilitySampler(0.25)
+
+	kept := 0
+	const total = 10_000
+	for range total {
+		if sampler.ShouldSample(GenerateTraceID()) {
+			kept++
+		}
+	}
+	if ratio := float64(kept) / total; ratio < 0.2 || ratio > 0.3 {
+		t.Fatalf("kept ratio = %.3f, want about 0.25", ratio)
+	}
+}
+
+func TestWithSampler_SetsSampledFromDecision(t *testing.T) {
+	dropped, err := NewSpan("api", "predict", "gpt-4o-mini", WithSampler(NewProbabilitySampler(0)))
+	if err != nil {
+		t.Fatalf("NewSpan returned error: %v", err)
+	}
+	if dropped.Sampled {
+		t.Fatalf("Sampled = true with rate 0")
+	}
+	if got := FormatTraceparent(dropped); !strings.HasSuffix(got, "-00") {
+		t.Fatalf("traceparent = %q, want not-sampled flag", got)
+	}
+
+	child, err := NewChildSpan(dropped, "api", "tokenize")
+	if err != nil {
+		t.Fatalf("NewChildSpan returned error: %v", err)
+	}
+	if child.Sampled {
+		t.Fatalf("child of unsampled parent is sampled")
+	}
+
+	kept, err := NewSpan("api", "predict", "gpt-4o-mini", WithSampler(NewProbabilitySampler(1)))
+	if err != nil {
+		t.Fatalf("NewSpan returned error: %v", err)
+	}
+	if !kept.Sampled {
+		t.Fatalf("Sampled = false with rate 1")
+	}
+	if got := FormatTraceparent(kept); !strings.HasSuffix(got, "-01") {
+		t.Fatalf("traceparent = %q, want sampled flag", got)
+	}
+}
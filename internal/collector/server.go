@@ -20,6 +20,9 @@ func NewServer(collector *Collector) *Server {
 	}
 }
 
+// SendSpanBatch enqueues each span. AcceptedCount matches Stats.Accepted:
+// spans queued for the sink. Every other span, including those the sampler
+// dropped, is counted in RejectedCount.
 func (s *Server) SendSpanBatch(
 	_ context.Context,
 	req *infertracepb.SendSpanBatchRequest,
@@ -28,7 +31,7 @@ func (s *Server) SendSpanBatch(
 	var rejected int32
 
 	for _, pbSpan := range req.GetSpans() {
-		span := Span{
+		span := &Span{
 			TraceID:           pbSpan.GetTraceId(),
 			SpanID:            pbSpan.GetSpanId(),
 			ServiceName:       pbSpan.GetServiceName(),
@@ -36,11 +39,12 @@ func (s *Server) SendSpanBatch(
 			ModelName:         pbSpan.GetModelName(),
 			StartTimeUnixNano: pbSpan.GetStartTimeUnixNano(),
 			DurationNanos:     pbSpan.GetDurationNanos(),
-			Status:            pbSpan.GetStatus(),
+			Status:            ParseStatusCode(pbSpan.GetStatus()),
+			Sampled:           true,
 		}
 
 		if err := span.ValidateForIngest(); err != nil {
-			s.collector.AddInvalid(1)
+			s.collector.RejectInvalid(span)
 			rejected++
 			continue
 		}
@@ -0,0 +1,23 @@
+package collector
+
+import "sync/atomic"
+
+// AttrServiceNameDefaulted marks a span whose caller passed an empty service
+// name and got DefaultServiceName instead, so the bug stays findable.
+const AttrServiceNameDefaulted = "service.name_defaulted"
+
+var defaultServiceName atomic.Pointer[string]
+
+// SetDefaultServiceName sets the service name substituted when a span is
+// started with an empty one. An empty name, the initial value, disables the
+// fallback so constructors return ErrEmptyServiceName.
+func SetDefaultServiceName(name string) {
+	defaultServiceName.Store(&name)
+}
+
+func DefaultServiceName() string {
+	if name := defaultServiceName.Load(); name != nil {
+		return *name
+	}
+	return ""
+}
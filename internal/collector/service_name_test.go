@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func withDefaultServiceName(t *testing.T, name string) {
+	t.Helper()
+	previous := DefaultServiceName()
+	SetDefaultServiceName(name)
+	t.Cleanup(func() { SetDefaultServiceName(previous) })
+}
+
+func TestDefaultServiceName_Substituted(t *testing.T) {
+	withDefaultServiceName(t, "unknown-service")
+
+	span, err := NewSpan("", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("NewSpan returned error: %v", err)
+	}
+	if span.ServiceName != "unknown-service" {
+		t.Fatalf("ServiceName = %q, want %q", span.ServiceName, "unknown-service")
+	}
+	if span.Attributes[AttrServiceNameDefaulted] != true {
+		t.Fatalf("%s = %v, want true", AttrServiceNameDefaulted, span.Attributes[AttrServiceNameDefaulted])
+	}
+	if err := span.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	child, err := NewChildSpan(span, "", "tokenize")
+	if err != nil || child.ServiceName != "unknown-service" {
+		t.Fatalf("NewChildSpan = %v, %v; want defaulted service", child, err)
+	}
+	if got := NewTracer("").StartSpan("predict").ServiceName; got != "unknown-service" {
+		t.Fatalf("Tracer span ServiceName = %q, want %q", got, "unknown-service")
+	}
+}
+
+func TestDefaultServiceName_ExplicitNameWins(t *testing.T) {
+	withDefaultServiceName(t, "unknown-service")
+
+	span, err := NewSpan("api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("NewSpan returned error: %v", err)
+	}
+	if _, ok := span.Attributes[AttrServiceNameDefaulted]; ok || span.ServiceName != "api" {
+		t.Fatalf("span = %q %v, want explicit name without marker", span.ServiceName, span.Attributes)
+	}
+}
+
+func TestDefaultServiceName_UnsetStillErrors(t *testing.T) {
+	if _, err := NewSpan("", "predict", "gpt-4o-mini"); !errors.Is(err, ErrEmptyServiceName) {
+		t.Fatalf("NewSpan error = %v, want %v", err, ErrEmptyServiceName)
+	}
+
+	span := NewTracer("").StartSpan("predict")
+	if err := span.Validate(); !errors.Is(err, ErrEmptyServiceName) {
+		t.Fatalf("Validate error = %v, want %v", err, ErrEmptyServiceName)
+	}
+}
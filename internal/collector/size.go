@@ -0,0 +1,54 @@
+package collector
+
+// EstimatedSize approximates the bytes a span's variable-length data occupies:
+// its string fields plus the keys and values of Attributes, Resource, Events
+// and Links. Fixed-size fields and map overhead are not counted, so a bare
+// span estimates to 0. Numbers count 8 bytes and bools 1.
+func (s *Span) EstimatedSize() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := len(s.TraceID) + len(s.SpanID) + len(s.ParentSpanID) +
+		len(s.ServiceName) + len(s.OperationName) + len(s.ModelName) +
+		len(s.StatusDescription)
+	size += attributesSize(s.Attributes) + attributesSize(s.Resource)
+	for _, event := range s.Events {
+		size += len(event.Name) + 8 + attributesSize(event.Attributes)
+	}
+	for _, link := range s.Links {
+		size += len(link.TraceID) + len(link.SpanID) + attributesSize(link.Attributes)
+	}
+	return size
+}
+
+func attributesSize(attrs map[string]any) int {
+	size := 0
+	for key, value := range attrs {
+		size += len(key) + valueSize(value)
+	}
+	return size
+}
+
+func valueSize(value any) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	case []string:
+		n := 0
+		for _, s := range v {
+			n += len(s)
+		}
+		return n
+	case bool:
+		return 1
+	case map[string]any:
+		return attributesSize(v)
+	case nil:
+		return 0
+	default:
+		return 8
+	}
+}
@@ -0,0 +1,41 @@
+package collector
+
+import "testing"
+
+func TestSpan_EstimatedSize(t *testing.T) {
+	span := &Span{}
+	if got := span.EstimatedSize(); got != 0 {
+		t.Fatalf("bare span EstimatedSize = %d, want 0", got)
+	}
+
+	span.TraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	span.SpanID = "00f067aa0ba902b7"
+	if got := span.EstimatedSize(); got != 48 {
+		t.Fatalf("EstimatedSize with IDs = %d, want 48", got)
+	}
+
+	previous := span.EstimatedSize()
+	steps := []struct {
+		name  string
+		apply func()
+		grows int
+	}{
+		{name: "string attribute", apply: func() { _ = span.SetAttribute("route", "/predict") }, grows: 5 + 8},
+		{name: "int attribute", apply: func() { _ = span.SetAttribute("tokens", 12) }, grows: 6 + 8},
+		{name: "event", apply: func() { span.AddEvent("first_token", map[string]any{"ok": true}) }, grows: 11 + 8 + 2 + 1},
+		{name: "link", apply: func() { span.AddLink("t", "s", nil) }, grows: 2},
+	}
+	for _, step := range steps {
+		step.apply()
+		got := span.EstimatedSize()
+		if got-previous != step.grows {
+			t.Fatalf("after %s EstimatedSize grew by %d, want %d", step.name, got-previous, step.grows)
+		}
+		previous = got
+	}
+
+	var nilSpan *Span
+	if got := nilSpan.EstimatedSize(); got != 0 {
+		t.Fatalf("nil span EstimatedSize = %d, want 0", got)
+	}
+}
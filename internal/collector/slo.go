@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Bucket returns the latency band of the span's Duration: the index of the
+// first threshold it does not exceed, or len(thresholds) if it exceeds them
+// all. thresholds must be sorted ascending.
+func (s *Span) Bucket(thresholds []time.Duration) int {
+	d := s.Duration()
+	return sort.Search(len(thresholds), func(i int) bool {
+		return d <= thresholds[i]
+	})
+}
+
+// SLOResult is the latency SLO compliance of one operation. Compliance is
+// Within / (Within + Breached).
+type SLOResult struct {
+	ServiceName   string
+	OperationName string
+	Within        int64
+	Breached      int64
+	Compliance    float64
+}
+
+// SLOReport counts, per operation, how many spans finished within a latency
+// target. Like MetricsAggregator it implements Exporter.
+type SLOReport struct {
+	target time.Duration
+
+	mu  sync.Mutex
+	ops map[operationKey]*SLOResult
+}
+
+// NewSLOReport uses target as the latency objective; a span whose Duration
+// equals target is within it.
+func NewSLOReport(target time.Duration) *SLOReport {
+	return &SLOReport{
+		target: target,
+		ops:    make(map[operationKey]*SLOResult),
+	}
+}
+
+func (r *SLOReport) Export(_ context.Context, spans []*Span) error {
+	for _, span := range spans {
+		r.Consume(span)
+	}
+	return nil
+}
+
+func (r *SLOReport) Consume(span *Span) {
+	if span == nil {
+		return
+	}
+	span.mu.Lock()
+	key := operationKey{service: span.ServiceName, operation: span.OperationName}
+	duration := time.Duration(span.DurationNanos)
+	span.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.ops[key]
+	if !ok {
+		result = &SLOResult{ServiceName: key.service, OperationName: key.operation}
+		r.ops[key] = result
+	}
+	if duration <= r.target {
+		result.Within++
+	} else {
+		result.Breached++
+	}
+	result.Compliance = float64(result.Within) / float64(result.Within+result.Breached)
+}
+
+// Compliance returns the fraction of an operation's spans within the target.
+func (r *SLOReport) Compliance(service, operation string) (float64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.ops[operationKey{service: service, operation: operation}]
+	if !ok {
+		return 0, false
+	}
+	return result.Compliance, true
+}
+
+// Results returns every operation's result sorted by service then operation.
+func (r *SLOReport) Results() []SLOResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]SLOResult, 0, len(r.ops))
+	for _, result := range r.ops {
+		out = append(out, *result)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ServiceName != out[j].ServiceName {
+			return out[i].ServiceName < out[j].ServiceName
+		}
+		return out[i].OperationName < out[j].OperationName
+	})
+	return out
+}
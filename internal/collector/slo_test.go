@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSpan_Bucket(t *testing.T) {
+	thresholds := []time.Duration{100 * time.Millisecond, 500 * time.Millisecond, time.Second}
+
+	tests := []struct {
+		duration time.Duration
+		want     int
+	}{
+		{duration: 0, want: 0},
+		{duration: 100 * time.Millisecond, want: 0},
+		{duration: 101 * time.Millisecond, want: 1},
+		{duration: 750 * time.Millisecond, want: 2},
+		{duration: time.Second, want: 2},
+		{duration: 3 * time.Second, want: 3},
+	}
+	for _, tc := range tests {
+		span := &Span{DurationNanos: int64(tc.duration)}
+		if got := span.Bucket(thresholds); got != tc.want {
+			t.Fatalf("Bucket(%v) = %d, want %d", tc.duration, got, tc.want)
+		}
+	}
+
+	var nilSpan *Span
+	if got := nilSpan.Bucket(thresholds); got != 0 {
+		t.Fatalf("nil span Bucket = %d, want 0", got)
+	}
+}
+
+func TestSLOReport_Compliance(t *testing.T) {
+	report := NewSLOReport(200 * time.Millisecond)
+	spans := []*Span{
+		{ServiceName: "api", OperationName: "predict", DurationNanos: int64(50 * time.Millisecond)},
+		{ServiceName: "api", OperationName: "predict", DurationNanos: int64(200 * time.Millisecond)},
+		{ServiceName: "api", OperationName: "predict", DurationNanos: int64(150 * time.Millisecond)},
+		{ServiceName: "api", OperationName: "predict", DurationNanos: int64(900 * time.Millisecond)},
+		{ServiceName: "api", OperationName: "embed", DurationNanos: int64(time.Second)},
+	}
+	if err := report.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	if got, ok := report.Compliance("api", "predict"); !ok || got != 0.75 {
+		t.Fatalf("Compliance(predict) = %v, %v; want 0.75, true", got, ok)
+	}
+	if _, ok := report.Compliance("api", "missing"); ok {
+		t.Fatalf("Compliance for unknown operation reported ok")
+	}
+
+	want := []SLOResult{
+		{ServiceName: "api", OperationName: "embed", Within: 0, Breached: 1, Compliance: 0},
+		{ServiceName: "api", OperationName: "predict", Within: 3, Breached: 1, Compliance: 0.75},
+	}
+	got := report.Results()
+	if len(got) != len(want) {
+		t.Fatalf("Results() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Results()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
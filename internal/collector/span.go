@@ -1,58 +1,477 @@
 package collector
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"sync"
 	"time"
-
-	"github.com/google/uuid"
 )
 
+var ErrSpanAlreadyEnded = errors.New("span already ended")
+
 type Span struct {
-	TraceID           string
-	SpanID            string
-	ParentSpanID      string
-	ServiceName       string
-	OperationName     string
-	ModelName         string
-	StartTimeUnixNano int64
-	DurationNanos     int64
-	Status            string
-}
-
-func NewSpan(serviceName, operationName, modelName string) (*Span, error) {
-	span := &Span{
-		SpanID:            uuid.New().String(),
-		ServiceName:       serviceName,
-		OperationName:     operationName,
-		ModelName:         modelName,
-		StartTimeUnixNano: time.Now().UnixNano(),
-	}
+	TraceID           string         `json:"trace_id"`
+	SpanID            string         `json:"span_id"`
+	ParentSpanID      string         `json:"parent_span_id,omitempty"`
+	ServiceName       string         `json:"service_name"`
+	OperationName     string         `json:"operation_name"`
+	ModelName         string         `json:"model_name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano int64          `json:"start_time_unix_nano"`
+	DurationNanos     int64          `json:"duration_nanos"`
+	Status            StatusCode     `json:"status"`
+	StatusDescription string         `json:"status_description,omitempty"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+	Events            []Event        `json:"events,omitempty"`
+	Links             []Link         `json:"links,omitempty"`
+	Resource          Resource       `json:"resource,omitempty"`
+	// Sampled is false when a sampler dropped the trace. Unsampled spans are
+	// still usable locally but BatchProcessor never exports them.
+	Sampled bool `json:"sampled"`
+
+	mu      sync.Mutex
+	clock   Clock
+	ids     IDGenerator
+	sampler Sampler
+	ended   bool
+	// durationSet means DurationNanos came from SetDuration and End must keep it.
+	durationSet bool
+	// start is the clock reading behind StartTimeUnixNano. When it carries a
+	// monotonic reading End measures against it, so wall clock steps (NTP)
+	// between start and end do not distort the duration.
+	start time.Time
+
+	limits   *AttributeLimits
+	watchdog *time.Timer
+	// watchdogGen identifies the armed watchdog; an expiry from a replaced or
+	// stopped timer carries an older value and is ignored.
+	watchdogGen uint64
+
+	// parent and inherit only live during construction, for
+	// WithInheritedAttributes; applySpanOptions clears them.
+	parent  *Span
+	inherit []string
+}
+
+func NewSpan(serviceName, operationName, modelName string, opts ...SpanOption) (*Span, error) {
+	span := newSpan(serviceName, operationName, modelName)
 
 	if span.ServiceName == "" {
-		return nil, errors.New("service_name is required")
+		return nil, ErrEmptyServiceName
 	}
 	if span.OperationName == "" {
-		return nil, errors.New("operation_name is required")
+		return nil, ErrEmptyOperationName
 	}
 	if span.ModelName == "" {
-		return nil, errors.New("model_name is required")
+		return nil, ErrEmptyModelName
+	}
+
+	applySpanOptions(span, opts)
+	return span, nil
+}
+
+// NewChildSpan starts a span in the parent's trace with ParentSpanID pointing at
+// the parent. The child inherits the parent's ModelName. A nil parent starts a new
+// root trace instead.
+func NewChildSpan(parent *Span, serviceName, operationName string, opts ...SpanOption) (*Span, error) {
+	span := newSpan(serviceName, operationName, "")
+	if span.ServiceName == "" {
+		return nil, ErrEmptyServiceName
+	}
+	if span.OperationName == "" {
+		return nil, ErrEmptyOperationName
+	}
+
+	if parent != nil {
+		WithParent(parent)(span)
+		parent.mu.Lock()
+		span.ModelName = parent.ModelName
+		parent.mu.Unlock()
 	}
 
+	applySpanOptions(span, opts)
 	return span, nil
 }
 
-func (s Span) ValidateForIngest() error {
+// newSpan substitutes DefaultServiceName for an empty serviceName; callers still
+// reject a name that is empty afterwards.
+func newSpan(serviceName, operationName, modelName string) *Span {
+	span := &Span{
+		ServiceName:   serviceName,
+		OperationName: operationName,
+		ModelName:     modelName,
+		Kind:          SpanKindInternal,
+		Attributes:    make(map[string]any),
+		Sampled:       true,
+	}
+	if serviceName == "" {
+		if name := DefaultServiceName(); name != "" {
+			span.ServiceName = name
+			span.Attributes[AttrServiceNameDefaulted] = true
+		}
+	}
+	return span
+}
+
+// applySpanOptions runs opts and then fills in IDs from the span's generator and
+// the start time from its clock, unless an option already set them. A sampler
+// only decides for root spans; children keep their parent's decision.
+func applySpanOptions(span *Span, opts []SpanOption) {
+	for _, opt := range opts {
+		opt(span)
+	}
+	ids := span.ids
+	if ids == nil {
+		ids = defaultIDGenerator
+	}
+	if span.TraceID == "" {
+		span.TraceID = ids.NewTraceID()
+	}
+	if span.SpanID == "" {
+		span.SpanID = ids.NewSpanID()
+	}
+	if span.StartTimeUnixNano == 0 {
+		span.start = span.now()
+		span.StartTimeUnixNano = span.start.UnixNano()
+	}
+	if span.sampler != nil && span.IsRoot() {
+		span.Sampled = span.sampler.ShouldSample(span.TraceID)
+	}
+	if span.parent != nil && len(span.inherit) > 0 {
+		span.inheritAttributes(span.parent, span.inherit)
+	}
+	span.parent, span.inherit = nil, nil
+}
+
+const AttrClockSkewDetected = "timing.clock_skew_detected"
+
+// End records DurationNanos from StartTimeUnixNano to now, unless SetDuration
+// already fixed it. Only the first call takes effect; later calls return
+// ErrSpanAlreadyEnded and keep the original duration.
+func (s *Span) End() error {
+	if s == nil {
+		return ErrNilSpan
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.endLocked(s.now())
+}
+
+// EndAll ends every span with one shared end time, read once from the first
+// open span's clock, and sets status on each as SetStatus(status, "") would.
+// Spans already ended and nil entries are left untouched. It returns how many
+// spans it ended.
+func EndAll(spans []*Span, status StatusCode) int {
+	var (
+		now     time.Time
+		haveNow bool
+		ended   int
+	)
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		span.mu.Lock()
+		if !span.ended {
+			if !haveNow {
+				now, haveNow = span.now(), true
+			}
+			_ = span.endLocked(now)
+			span.Status = status
+			span.StatusDescription = ""
+			ended++
+		}
+		span.mu.Unlock()
+	}
+	return ended
+}
+
+// endLocked clamps a negative duration to 0 and flags it with
+// AttrClockSkewDetected: a start time from a skewed clock is an anomaly worth
+// seeing, not a negative latency.
+func (s *Span) endLocked(now time.Time) error {
+	if s.ended {
+		return ErrSpanAlreadyEnded
+	}
+	if s.watchdog != nil {
+		s.watchdog.Stop()
+		s.watchdog = nil
+	}
+	s.ended = true
+	if s.durationSet {
+		return nil
+	}
+	if hasMonotonic(s.start) {
+		s.DurationNanos = now.Sub(s.start).Nanoseconds()
+	} else {
+		s.DurationNanos = now.UnixNano() - s.StartTimeUnixNano
+	}
+	if s.DurationNanos < 0 {
+		s.DurationNanos = 0
+		if s.Attributes == nil {
+			s.Attributes = make(map[string]any)
+		}
+		s.Attributes[AttrClockSkewDetected] = true
+	}
+	return nil
+}
+
+// hasMonotonic reports whether t carries a monotonic clock reading, which
+// Round(0) strips.
+func hasMonotonic(t time.Time) bool {
+	return t != t.Round(0)
+}
+
+// SetDuration records a known duration, e.g. for spans imported from another
+// system. A later End marks the span ended but keeps d.
+func (s *Span) SetDuration(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.DurationNanos = d.Nanoseconds()
+	s.durationSet = true
+}
+
+// IsRoot reports whether the span starts its trace, i.e. has no ParentSpanID.
+func (s *Span) IsRoot() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ParentSpanID == ""
+}
+
+func (s *Span) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Duration(s.DurationNanos)
+}
+
+func (s *Span) EndTime() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Unix(0, s.StartTimeUnixNano+s.DurationNanos)
+}
+
+// Clone returns an independent copy: Attributes, Events and Links are deep
+// copied so the two spans can be mutated separately. Resource stays shared since
+// it describes the process, not the span.
+func (s *Span) Clone() *Span {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clone := &Span{
+		TraceID:           s.TraceID,
+		SpanID:            s.SpanID,
+		ParentSpanID:      s.ParentSpanID,
+		ServiceName:       s.ServiceName,
+		OperationName:     s.OperationName,
+		ModelName:         s.ModelName,
+		Kind:              s.Kind,
+		StartTimeUnixNano: s.StartTimeUnixNano,
+		DurationNanos:     s.DurationNanos,
+		Status:            s.Status,
+		StatusDescription: s.StatusDescription,
+		Attributes:        maps.Clone(s.Attributes),
+		Resource:          s.Resource,
+		clock:             s.clock,
+		Sampled:           s.Sampled,
+		ended:             s.ended,
+		durationSet:       s.durationSet,
+		start:             s.start,
+		limits:            s.limits,
+	}
+	if s.Events != nil {
+		clone.Events = make([]Event, len(s.Events))
+		for i, event := range s.Events {
+			event.Attributes = maps.Clone(event.Attributes)
+			clone.Events[i] = event
+		}
+	}
+	if s.Links != nil {
+		clone.Links = make([]Link, len(s.Links))
+		for i, link := range s.Links {
+			link.Attributes = maps.Clone(link.Attributes)
+			clone.Links[i] = link
+		}
+	}
+	return clone
+}
+
+// SchemaVersion is written as "schema_version" in every span's JSON. Bump it
+// when the encoding changes and teach UnmarshalJSON the older versions.
+// Version 0 is JSON from before the marker existed.
+const SchemaVersion = 1
+
+type plainSpan Span
+
+type versionedSpan struct {
+	SchemaVersion int `json:"schema_version"`
+	*plainSpan
+}
+
+// MarshalJSON output is deterministic: encoding/json writes Attributes,
+// Resource and nested maps in sorted key order, so the same span always encodes
+// to the same bytes and golden files can be compared byte for byte. The span is
+// locked while it is encoded, so concurrent setters cannot tear the snapshot.
+func (s *Span) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.Marshal(versionedSpan{SchemaVersion: SchemaVersion, plainSpan: (*plainSpan)(s)})
+}
+
+// UnmarshalJSON accepts every schema version up to SchemaVersion. A span
+// without a "sampled" field, which version 0 never wrote, is treated as
+// sampled so spans from older clients keep flowing through the pipeline.
+func (s *Span) UnmarshalJSON(data []byte) error {
+	s.Sampled = true
+	return json.Unmarshal(data, &versionedSpan{plainSpan: (*plainSpan)(s)})
+}
+
+// SetAttribute stores value under key, subject to the span's AttributeLimits.
+// Integer and float32 values are widened to int64 and float64; other types
+// outside string/int64/float64/bool/[]string are dropped with
+// ErrUnsupportedAttributeType. A new key past MaxCount is dropped with
+// ErrAttributeLimitReached.
+func (s *Span) SetAttribute(key string, value any) error {
+	if s == nil {
+		return ErrNilSpan
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limits := s.attributeLimits()
+	value, err := normalizeAttributeValue(value, limits.MaxValueLength)
+	if err != nil {
+		return err
+	}
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]any)
+	}
+	if _, exists := s.Attributes[key]; !exists && limits.MaxCount > 0 && len(s.Attributes) >= limits.MaxCount {
+		return ErrAttributeLimitReached
+	}
+	s.Attributes[key] = value
+	return nil
+}
+
+func (s *Span) GetAttribute(key string) (any, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.Attributes[key]
+	return value, ok
+}
+
+// GetString returns the attribute under key if it is a string.
+func (s *Span) GetString(key string) (string, bool) {
+	return getTyped[string](s, key)
+}
+
+// GetInt64 returns the attribute under key if it is an int64. Note that
+// attributes decoded from JSON arrive as float64.
+func (s *Span) GetInt64(key string) (int64, bool) {
+	return getTyped[int64](s, key)
+}
+
+func (s *Span) GetFloat64(key string) (float64, bool) {
+	return getTyped[float64](s, key)
+}
+
+func (s *Span) GetBool(key string) (bool, bool) {
+	return getTyped[bool](s, key)
+}
+
+func getTyped[T any](s *Span, key string) (T, bool) {
+	value, _ := s.GetAttribute(key)
+	typed, ok := value.(T)
+	return typed, ok
+}
+
+func (s *Span) ValidateForIngest() error {
+	if s == nil {
+		return ErrNilSpan
+	}
 	if s.ServiceName == "" {
-		return errors.New("service_name is required")
+		return ErrEmptyServiceName
 	}
 	if s.ModelName == "" {
-		return errors.New("model_name is required")
+		return ErrEmptyModelName
 	}
 	if s.StartTimeUnixNano <= 0 {
-		return errors.New("start_time_unix_nano must be > 0")
+		return ErrInvalidStartTime
 	}
 	if s.DurationNanos <= 0 {
-		return errors.New("duration_nanos must be > 0")
+		return fmt.Errorf("%w: must be > 0", ErrInvalidDuration)
 	}
 	return nil
 }
+
+// Validate checks that a span is well-formed enough to export and reports every
+// problem it finds, joined into a single error.
+func (s *Span) Validate() error {
+	errs := s.validationErrors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrSpanValidation, errors.Join(errs...))
+}
+
+// validationErrors lists every problem Validate reports, one error each.
+func (s *Span) validationErrors() []error {
+	if s == nil {
+		return []error{ErrNilSpan}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	if s.TraceID == "" {
+		errs = append(errs, ErrEmptyTraceID)
+	} else if !isLowerHex(s.TraceID, traceIDHexLen) {
+		errs = append(errs, fmt.Errorf("%w: %q must be %d lowercase hex characters", ErrInvalidTraceID, s.TraceID, traceIDHexLen))
+	}
+	if s.SpanID == "" {
+		errs = append(errs, ErrEmptySpanID)
+	}
+	if s.ServiceName == "" {
+		errs = append(errs, ErrEmptyServiceName)
+	}
+	if s.StartTimeUnixNano <= 0 {
+		errs = append(errs, ErrInvalidStartTime)
+	}
+	if s.DurationNanos < 0 {
+		errs = append(errs, fmt.Errorf("%w: must be >= 0", ErrInvalidDuration))
+	}
+	for i, link := range s.Links {
+		if link.TraceID == "" {
+			errs = append(errs, fmt.Errorf("%w: links[%d].trace_id is required", ErrInvalidLink, i))
+		}
+		if link.SpanID == "" {
+			errs = append(errs, fmt.Errorf("%w: links[%d].span_id is required", ErrInvalidLink, i))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,202 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSpanJSON_RoundTrip(t *testing.T) {
+	original := &Span{
+		TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:            "00f067aa0ba902b7",
+		ParentSpanID:      "b7ad6b7169203331",
+		ServiceName:       "inference-api",
+		OperationName:     "predict",
+		ModelName:         "gpt-4o-mini",
+		StartTimeUnixNano: 1_700_000_000_000_000_000,
+		DurationNanos:     42_000_000,
+		Status:            StatusError,
+		StatusDescription: "model timed out",
+		Attributes: map[string]any{
+			"gpu.id":            "gpu-0",
+			"llm.prompt_tokens": float64(128),
+			"cache.hit":         true,
+		},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var decoded Span
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, &decoded) {
+		t.Fatalf("round trip mismatch:\n got=%+v\nwant=%+v", &decoded, original)
+	}
+}
+
+func TestSpanJSON_SnakeCaseAndOmitsEmptyParent(t *testing.T) {
+	span := &Span{
+		TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:            "00f067aa0ba902b7",
+		ServiceName:       "inference-api",
+		StartTimeUnixNano: 1,
+		Status:            StatusOK,
+	}
+
+	data, err := json.Marshal(span)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	body := string(data)
+
+	if strings.Contains(body, "parent_span_id") {
+		t.Fatalf("expected parent_span_id to be omitted for root span: %s", body)
+	}
+	for _, key := range []string{`"trace_id"`, `"span_id"`, `"service_name"`, `"start_time_unix_nano"`, `"status":"ok"`} {
+		if !strings.Contains(body, key) {
+			t.Fatalf("expected %s in %s", key, body)
+		}
+	}
+}
+
+func TestSpanJSON_MissingSampledDefaultsTrue(t *testing.T) {
+	var span Span
+	if err := json.Unmarshal([]byte(`{"trace_id":"t","span_id":"s"}`), &span); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !span.Sampled {
+		t.Fatalf("Sampled = false for span without the field")
+	}
+	if err := json.Unmarshal([]byte(`{"trace_id":"t","span_id":"s","sampled":false}`), &span); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if span.Sampled {
+		t.Fatalf("Sampled = true despite explicit false")
+	}
+}
+
+func TestSpanJSON_EmitsSchemaVersion(t *testing.T) {
+	raw, err := json.Marshal(&Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if got := fields["schema_version"]; got != float64(SchemaVersion) {
+		t.Fatalf("schema_version = %v, want %d", got, SchemaVersion)
+	}
+	if got := fields["trace_id"]; got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace_id = %v, want span fields alongside the version", got)
+	}
+}
+
+func TestSpanJSON_DecodesSchemaVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		sampled bool
+	}{
+		{
+			name:    "version 0",
+			payload: `{"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","service_name":"api","start_time_unix_nano":5}`,
+			sampled: true,
+		},
+		{
+			name:    "version 1",
+			payload: `{"schema_version":1,"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","service_name":"api","start_time_unix_nano":5,"sampled":false}`,
+			sampled: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var span Span
+			if err := json.Unmarshal([]byte(tc.payload), &span); err != nil {
+				t.Fatalf("Unmarshal returned error: %v", err)
+			}
+			if err := span.Validate(); err != nil {
+				t.Fatalf("Validate returned error: %v", err)
+			}
+			if span.ServiceName != "api" || span.Sampled != tc.sampled {
+				t.Fatalf("span = %q sampled=%v, want api sampled=%v", span.ServiceName, span.Sampled, tc.sampled)
+			}
+		})
+	}
+}
+
+func TestSpanJSON_Deterministic(t *testing.T) {
+	span := &Span{
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		Attributes: map[string]any{"zeta": 1, "alpha": "a", "mid": true, "nested": map[string]any{"y": 1, "b": 2}},
+		Resource:   Resource{"service.version": "1.2.0", "host.name": "node-7"},
+	}
+
+	first, err := json.Marshal(span)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	for range 20 {
+		again, err := json.Marshal(span)
+		if err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+		if string(again) != string(first) {
+			t.Fatalf("encodings differ:\n%s\n%s", first, again)
+		}
+	}
+}
+
+func TestSpanJSON_AttributeKeysSorted(t *testing.T) {
+	span := &Span{Attributes: map[string]any{"zeta": 1, "alpha": "a", "mid": true}}
+
+	raw, err := json.Marshal(span)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	alpha := strings.Index(string(raw), `"alpha"`)
+	mid := strings.Index(string(raw), `"mid"`)
+	zeta := strings.Index(string(raw), `"zeta"`)
+	if alpha < 0 || !(alpha < mid && mid < zeta) {
+		t.Fatalf("attribute keys not sorted in %s", raw)
+	}
+}
+
+func TestSpanJSON_ConcurrentWithSetters(t *testing.T) {
+	span, _ := NewSpan("inference-api", "predict", "gpt-4o-mini")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			_ = span.SetAttribute(fmt.Sprintf("key.%d", i), int64(i))
+			span.SetStatus(StatusOK, "")
+		}
+	}()
+	for range 100 {
+		if _, err := json.Marshal(span); err != nil {
+			t.Fatalf("Marshal returned error: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestSpanJSON_NilSpan(t *testing.T) {
+	var span *Span
+	got, err := span.MarshalJSON()
+	if err != nil || string(got) != "null" {
+		t.Fatalf("MarshalJSON(nil) = %s, %v, want null", got, err)
+	}
+}
@@ -0,0 +1,272 @@
+package collector
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// DedupMode decides what SpanStore.Add does with a span whose (TraceID, SpanID)
+// is already stored.
+type DedupMode int
+
+const (
+	// DedupIgnore keeps the first copy and drops later ones.
+	DedupIgnore DedupMode = iota
+	// DedupMerge copies the later copy's non-empty fields onto the stored span,
+	// e.g. a duration that only arrived on a retry.
+	DedupMerge
+)
+
+type SpanStore struct {
+	mu     sync.RWMutex
+	traces map[string][]*Span
+	index  map[spanKey]*Span
+	dedup  DedupMode
+	clock  Clock
+
+	maxSpansPerTrace int
+	dropped          int64
+	onDrop           DropFunc
+
+	// lastUpdated is when each trace last gained or merged a span, for the idle
+	// reaper.
+	lastUpdated map[string]time.Time
+
+	// traceAttrs holds attributes that describe a whole trace rather than any
+	// one span; see SetTraceAttribute.
+	traceAttrs map[string]map[string]any
+
+	// subscribers receive every newly added span; see Subscribe.
+	subscribers map[<-chan *Span]chan *Span
+}
+
+type spanKey struct {
+	traceID string
+	spanID  string
+}
+
+type SpanStoreOption func(*SpanStore)
+
+func WithDedupMode(mode DedupMode) SpanStoreOption {
+	return func(s *SpanStore) {
+		s.dedup = mode
+	}
+}
+
+// WithMaxSpansPerTrace caps how many spans one trace may hold; further spans
+// for that trace are dropped and counted. 0 means unlimited.
+func WithMaxSpansPerTrace(n int) SpanStoreOption {
+	return func(s *SpanStore) {
+		s.maxSpansPerTrace = n
+	}
+}
+
+// WithStoreOnDrop calls fn with DropCap for every span dropped by the
+// per-trace cap. fn runs after the store is unlocked, so it may use the store.
+func WithStoreOnDrop(fn DropFunc) SpanStoreOption {
+	return func(s *SpanStore) {
+		s.onDrop = fn
+	}
+}
+
+// WithStoreClock makes the store timestamp trace updates with clock.
+func WithStoreClock(clock Clock) SpanStoreOption {
+	return func(s *SpanStore) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+func NewSpanStore(opts ...SpanStoreOption) *SpanStore {
+	s := &SpanStore{
+		traces:      make(map[string][]*Span),
+		index:       make(map[spanKey]*Span),
+		clock:       realClock{},
+		lastUpdated: make(map[string]time.Time),
+		traceAttrs:  make(map[string]map[string]any),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add stores span and reports whether it was new. A duplicate is handled
+// according to the store's DedupMode.
+func (s *SpanStore) Add(span *Span) bool {
+	if span == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	added, capped := s.addLocked(span)
+	s.mu.Unlock()
+
+	if capped {
+		s.onDrop.call(span, DropCap)
+	}
+	return added
+}
+
+// addLocked stores span, reporting whether it was added and whether it was
+// dropped by the per-trace cap.
+func (s *SpanStore) addLocked(span *Span) (added, capped bool) {
+	key := spanKey{traceID: span.TraceID, spanID: span.SpanID}
+	if existing, ok := s.index[key]; ok {
+		if s.dedup == DedupMerge && existing != span {
+			mergeSpan(existing, span)
+			s.lastUpdated[span.TraceID] = s.clock.Now()
+		}
+		return false, false
+	}
+	if s.maxSpansPerTrace > 0 && len(s.traces[span.TraceID]) >= s.maxSpansPerTrace {
+		s.dropped++
+		return false, true
+	}
+
+	s.index[key] = span
+	s.traces[span.TraceID] = append(s.traces[span.TraceID], span)
+	s.lastUpdated[span.TraceID] = s.clock.Now()
+	s.publishLocked(span)
+	return true, false
+}
+
+func mergeSpan(dst, src *Span) {
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if src.ParentSpanID != "" {
+		dst.ParentSpanID = src.ParentSpanID
+	}
+	if src.ServiceName != "" {
+		dst.ServiceName = src.ServiceName
+	}
+	if src.OperationName != "" {
+		dst.OperationName = src.OperationName
+	}
+	if src.ModelName != "" {
+		dst.ModelName = src.ModelName
+	}
+	if src.Kind != SpanKindUnspecified {
+		dst.Kind = src.Kind
+	}
+	if src.StartTimeUnixNano != 0 && src.StartTimeUnixNano != dst.StartTimeUnixNano {
+		dst.StartTimeUnixNano = src.StartTimeUnixNano
+		// The monotonic start no longer matches, so End must use wall time.
+		dst.start = time.Time{}
+	}
+	if src.DurationNanos != 0 {
+		dst.DurationNanos = src.DurationNanos
+	}
+	if src.Status != StatusUnset {
+		dst.Status = src.Status
+		dst.StatusDescription = src.StatusDescription
+	}
+	if len(src.Attributes) > 0 {
+		if dst.Attributes == nil {
+			dst.Attributes = make(map[string]any, len(src.Attributes))
+		}
+		maps.Copy(dst.Attributes, src.Attributes)
+	}
+	if len(src.Events) > 0 {
+		dst.Events = src.Events
+	}
+	if len(src.Links) > 0 {
+		dst.Links = src.Links
+	}
+	if len(src.Resource) > 0 {
+		dst.Resource = src.Resource
+	}
+}
+
+// Has reports whether the span is already stored.
+func (s *SpanStore) Has(traceID, spanID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.index[spanKey{traceID: traceID, spanID: spanID}]
+	return ok
+}
+
+// DroppedSpans reports how many spans Add has discarded for exceeding the
+// per-trace cap.
+func (s *SpanStore) DroppedSpans() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dropped
+}
+
+// GetTrace returns the trace's spans in insertion order. The returned slice is a
+// copy, so callers may reorder it without affecting the store.
+func (s *SpanStore) GetTrace(traceID string) ([]*Span, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	spans, ok := s.traces[traceID]
+	if !ok {
+		return nil, false
+	}
+
+	out := make([]*Span, len(spans))
+	copy(out, spans)
+	return out, true
+}
+
+func (s *SpanStore) delete(traceID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteLocked(traceID)
+}
+
+// take removes the trace and returns its spans in one locked step, so no span
+// added concurrently can be deleted unseen.
+func (s *SpanStore) take(traceID string) []*Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spans := s.traces[traceID]
+	s.deleteLocked(traceID)
+	return spans
+}
+
+func (s *SpanStore) deleteLocked(traceID string) {
+	for _, span := range s.traces[traceID] {
+		delete(s.index, spanKey{traceID: traceID, spanID: span.SpanID})
+	}
+	delete(s.traces, traceID)
+	delete(s.lastUpdated, traceID)
+	delete(s.traceAttrs, traceID)
+}
+
+// IsTraceComplete reports whether the trace has exactly one root span and every
+// other span's parent is present. A missing parent usually means a dropped span.
+func (s *SpanStore) IsTraceComplete(traceID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	spans := s.traces[traceID]
+	if len(spans) == 0 {
+		return false
+	}
+
+	ids := make(map[string]struct{}, len(spans))
+	for _, span := range spans {
+		ids[span.SpanID] = struct{}{}
+	}
+
+	roots := 0
+	for _, span := range spans {
+		if span.IsRoot() {
+			roots++
+			continue
+		}
+		if _, ok := ids[span.ParentSpanID]; !ok {
+			return false
+		}
+	}
+	return roots == 1
+}
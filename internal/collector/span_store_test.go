@@ -0,0 +1,215 @@
+package collector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSpanStore_GetTraceReturnsInsertionOrder(t *testing.T) {
+	s := NewSpanStore()
+
+	root := &Span{TraceID: "trace-1", SpanID: "a"}
+	child := &Span{TraceID: "trace-1", SpanID: "b", ParentSpanID: "a"}
+	other := &Span{TraceID: "trace-2", SpanID: "c"}
+	grandchild := &Span{TraceID: "trace-1", SpanID: "d", ParentSpanID: "b"}
+
+	s.Add(root)
+	s.Add(child)
+	s.Add(other)
+	s.Add(grandchild)
+
+	spans, ok := s.GetTrace("trace-1")
+	if !ok {
+		t.Fatalf("expected trace-1 to exist")
+	}
+	if got, want := len(spans), 3; got != want {
+		t.Fatalf("span count = %d, want %d", got, want)
+	}
+	for i, want := range []string{"a", "b", "d"} {
+		if spans[i].SpanID != want {
+			t.Fatalf("spans[%d] = %q, want %q", i, spans[i].SpanID, want)
+		}
+	}
+}
+
+func TestSpanStore_MissingTrace(t *testing.T) {
+	s := NewSpanStore()
+	s.Add(&Span{TraceID: "trace-1", SpanID: "a"})
+
+	spans, ok := s.GetTrace("does-not-exist")
+	if ok {
+		t.Fatalf("expected missing trace to report ok=false")
+	}
+	if spans != nil {
+		t.Fatalf("expected nil spans for missing trace, got %v", spans)
+	}
+}
+
+func TestSpanStore_IsTraceComplete(t *testing.T) {
+	tests := []struct {
+		name  string
+		spans []*Span
+		want  bool
+	}{
+		{
+			name: "complete trace",
+			spans: []*Span{
+				{SpanID: "a"},
+				{SpanID: "b", ParentSpanID: "a"},
+				{SpanID: "c", ParentSpanID: "b"},
+			},
+			want: true,
+		},
+		{
+			name: "missing intermediate parent",
+			spans: []*Span{
+				{SpanID: "a"},
+				{SpanID: "c", ParentSpanID: "b"},
+			},
+			want: false,
+		},
+		{
+			name: "two roots",
+			spans: []*Span{
+				{SpanID: "a"},
+				{SpanID: "b"},
+			},
+			want: false,
+		},
+		{
+			name: "no root",
+			spans: []*Span{
+				{SpanID: "b", ParentSpanID: "a"},
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewSpanStore()
+			for _, span := range tc.spans {
+				span.TraceID = "trace-1"
+				s.Add(span)
+			}
+			if got := s.IsTraceComplete("trace-1"); got != tc.want {
+				t.Fatalf("IsTraceComplete = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if NewSpanStore().IsTraceComplete("missing") {
+		t.Fatalf("expected missing trace to be incomplete")
+	}
+}
+
+func TestSpanStore_DuplicateIgnored(t *testing.T) {
+	s := NewSpanStore()
+
+	first := &Span{TraceID: "trace-1", SpanID: "a", OperationName: "predict"}
+	retry := &Span{TraceID: "trace-1", SpanID: "a", OperationName: "retried", DurationNanos: 10}
+
+	if !s.Add(first) {
+		t.Fatalf("first Add = false, want true")
+	}
+	if s.Add(retry) {
+		t.Fatalf("duplicate Add = true, want false")
+	}
+
+	spans, _ := s.GetTrace("trace-1")
+	if len(spans) != 1 {
+		t.Fatalf("span count = %d, want 1", len(spans))
+	}
+	if spans[0].OperationName != "predict" || spans[0].DurationNanos != 0 {
+		t.Fatalf("stored span changed by ignored duplicate: %+v", spans[0])
+	}
+}
+
+func TestSpanStore_DuplicateMerged(t *testing.T) {
+	s := NewSpanStore(WithDedupMode(DedupMerge))
+
+	s.Add(&Span{TraceID: "trace-1", SpanID: "a", OperationName: "predict", Attributes: map[string]any{"k": "v"}})
+	if s.Add(&Span{TraceID: "trace-1", SpanID: "a", DurationNanos: 250, Attributes: map[string]any{"extra": true}}) {
+		t.Fatalf("duplicate Add = true, want false")
+	}
+
+	spans, _ := s.GetTrace("trace-1")
+	if len(spans) != 1 {
+		t.Fatalf("span count = %d, want 1", len(spans))
+	}
+	got := spans[0]
+	if got.DurationNanos != 250 {
+		t.Fatalf("DurationNanos = %d, want 250", got.DurationNanos)
+	}
+	if got.OperationName != "predict" {
+		t.Fatalf("OperationName = %q, want empty field not to overwrite", got.OperationName)
+	}
+	if got.Attributes["k"] != "v" || got.Attributes["extra"] != true {
+		t.Fatalf("Attributes = %v, want merged", got.Attributes)
+	}
+}
+
+func TestSpanStore_MergedStartTimeDrivesEnd(t *testing.T) {
+	s := NewSpanStore(WithDedupMode(DedupMerge))
+	span, _ := NewSpan("api", "predict", "gpt-4o-mini")
+	s.Add(span)
+
+	earlier := span.StartTimeUnixNano - int64(time.Hour)
+	s.Add(&Span{TraceID: span.TraceID, SpanID: span.SpanID, StartTimeUnixNano: earlier})
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	if got := span.Duration(); got < time.Hour {
+		t.Fatalf("Duration = %v, want at least 1h from the merged start", got)
+	}
+}
+
+func TestSpanStore_DeleteForgetsDedupIndex(t *testing.T) {
+	s := NewSpanStore()
+	s.Add(&Span{TraceID: "trace-1", SpanID: "a"})
+	s.delete("trace-1")
+
+	if !s.Add(&Span{TraceID: "trace-1", SpanID: "a"}) {
+		t.Fatalf("Add after delete = false, want true")
+	}
+}
+
+func TestSpanStore_MaxSpansPerTrace(t *testing.T) {
+	s := NewSpanStore(WithMaxSpansPerTrace(3))
+
+	for i := range 5 {
+		added := s.Add(&Span{TraceID: "runaway", SpanID: fmt.Sprintf("%016x", i)})
+		if want := i < 3; added != want {
+			t.Fatalf("Add #%d = %v, want %v", i, added, want)
+		}
+	}
+	if !s.Add(&Span{TraceID: "other", SpanID: "a"}) {
+		t.Fatalf("cap on one trace affected another")
+	}
+
+	spans, _ := s.GetTrace("runaway")
+	if len(spans) != 3 {
+		t.Fatalf("stored %d spans, want 3", len(spans))
+	}
+	if got := s.DroppedSpans(); got != 2 {
+		t.Fatalf("DroppedSpans = %d, want 2", got)
+	}
+
+	if s.Add(&Span{TraceID: "runaway", SpanID: fmt.Sprintf("%016x", 0)}) {
+		t.Fatalf("duplicate of stored span reported as new")
+	}
+	if got := s.DroppedSpans(); got != 2 {
+		t.Fatalf("duplicate counted as dropped: DroppedSpans = %d", got)
+	}
+}
+
+func TestSpanStore_ZeroCapIsUnlimited(t *testing.T) {
+	s := NewSpanStore(WithMaxSpansPerTrace(0))
+	for i := range 100 {
+		s.Add(&Span{TraceID: "t", SpanID: fmt.Sprintf("%016x", i)})
+	}
+	if spans, _ := s.GetTrace("t"); len(spans) != 100 || s.DroppedSpans() != 0 {
+		t.Fatalf("stored %d, dropped %d; want 100, 0", len(spans), s.DroppedSpans())
+	}
+}
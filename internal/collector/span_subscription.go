@@ -0,0 +1,42 @@
+package collector
+
+// subscriberBuffer is how many spans a subscriber may fall behind before
+// further spans are dropped for it.
+const subscriberBuffer = 256
+
+// Subscribe returns a channel that receives each span the moment Add stores
+// it, for live tailing ahead of complete-trace export. Duplicates and capped
+// spans are not delivered. Add never blocks on a subscriber: a full channel
+// misses spans. Call Unsubscribe when done.
+func (s *SpanStore) Subscribe() <-chan *Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan *Span, subscriberBuffer)
+	if s.subscribers == nil {
+		s.subscribers = make(map[<-chan *Span]chan *Span)
+	}
+	s.subscribers[ch] = ch
+	return ch
+}
+
+// Unsubscribe stops delivery to ch and closes it. Spans already buffered can
+// still be drained. Unknown channels are ignored.
+func (s *SpanStore) Unsubscribe(ch <-chan *Span) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sub, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(sub)
+	}
+}
+
+func (s *SpanStore) publishLocked(span *Span) {
+	for _, sub := range s.subscribers {
+		select {
+		case sub <- span:
+		default:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSpanStore_SubscribeReceivesAddedSpans(t *testing.T) {
+	store := NewSpanStore()
+	ch := store.Subscribe()
+	t.Cleanup(func() { store.Unsubscribe(ch) })
+
+	root := &Span{TraceID: "t1", SpanID: "root"}
+	child := &Span{TraceID: "t1", SpanID: "child", ParentSpanID: "root"}
+	store.Add(root)
+	store.Add(&Span{TraceID: "t1", SpanID: "root"}) // duplicate, not delivered
+	store.Add(child)
+
+	for _, want := range []*Span{root, child} {
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Fatalf("received %s, want %s", got.SpanID, want.SpanID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want.SpanID)
+		}
+	}
+	select {
+	case got := <-ch:
+		t.Fatalf("received unexpected span %s", got.SpanID)
+	default:
+	}
+}
+
+func TestSpanStore_UnsubscribeStopsDelivery(t *testing.T) {
+	store := NewSpanStore()
+	ch := store.Subscribe()
+
+	received := make(chan string, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for span := range ch {
+			received <- span.SpanID
+		}
+	}()
+
+	store.Add(&Span{TraceID: "t1", SpanID: "before"})
+	if got := <-received; got != "before" {
+		t.Fatalf("received %s, want before", got)
+	}
+
+	store.Unsubscribe(ch)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("subscriber goroutine still running after Unsubscribe")
+	}
+
+	store.Add(&Span{TraceID: "t1", SpanID: "after"})
+	if len(received) != 0 {
+		t.Fatalf("received %s after Unsubscribe", <-received)
+	}
+	store.Unsubscribe(ch) // second call is a no-op
+}
+
+func TestSpanStore_SlowSubscriberDoesNotBlockAdd(t *testing.T) {
+	store := NewSpanStore()
+	ch := store.Subscribe()
+	t.Cleanup(func() { store.Unsubscribe(ch) })
+
+	for i := range subscriberBuffer + 10 {
+		store.Add(&Span{TraceID: "t1", SpanID: fmt.Sprintf("s%d", i)})
+	}
+	if got := len(ch); got != subscriberBuffer {
+		t.Fatalf("buffered = %d, want %d", got, subscriberBuffer)
+	}
+}
@@ -1,6 +1,13 @@
 package collector
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
 
 func TestNewSpan_SetsRequiredFields(t *testing.T) {
 	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
@@ -10,6 +17,9 @@ func TestNewSpan_SetsRequiredFields(t *testing.T) {
 	if span.SpanID == "" {
 		t.Fatalf("expected non-empty SpanID")
 	}
+	if len(span.TraceID) != 32 {
+		t.Fatalf("expected 32-character TraceID, got %q", span.TraceID)
+	}
 	if span.ServiceName != "inference-api" {
 		t.Fatalf("got %q, want inference-api", span.ServiceName)
 	}
@@ -41,15 +51,313 @@ func TestNewSpan_RejectsMissingRequiredFields(t *testing.T) {
 	}
 }
 
+func TestGenerateTraceID_LowercaseHexAndUnique(t *testing.T) {
+	first := GenerateTraceID()
+	second := GenerateTraceID()
+
+	if !isLowerHex(first, 32) {
+		t.Fatalf("trace id %q is not 32 lowercase hex characters", first)
+	}
+	if !isLowerHex(second, 32) {
+		t.Fatalf("trace id %q is not 32 lowercase hex characters", second)
+	}
+	if first == second {
+		t.Fatalf("expected distinct trace ids, got %q twice", first)
+	}
+}
+
+func TestNewChildSpan_LinksToParent(t *testing.T) {
+	parent, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	child, err := NewChildSpan(parent, "tokenizer", "encode")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if child.TraceID != parent.TraceID {
+		t.Fatalf("child trace id = %q, want %q", child.TraceID, parent.TraceID)
+	}
+	if child.ParentSpanID != parent.SpanID {
+		t.Fatalf("child parent span id = %q, want %q", child.ParentSpanID, parent.SpanID)
+	}
+	if child.SpanID == "" || child.SpanID == parent.SpanID {
+		t.Fatalf("expected fresh child span id, got %q (parent %q)", child.SpanID, parent.SpanID)
+	}
+	if child.ServiceName != "tokenizer" || child.OperationName != "encode" {
+		t.Fatalf("unexpected names: %q/%q", child.ServiceName, child.OperationName)
+	}
+	if child.ModelName != "gpt-4o-mini" {
+		t.Fatalf("child model name = %q, want gpt-4o-mini", child.ModelName)
+	}
+}
+
+func TestNewChildSpan_NilParentStartsNewTrace(t *testing.T) {
+	span, err := NewChildSpan(nil, "inference-api", "predict")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(span.TraceID) != 32 {
+		t.Fatalf("trace id = %q, want 32 hex characters", span.TraceID)
+	}
+	if span.ParentSpanID != "" {
+		t.Fatalf("expected empty parent span id, got %q", span.ParentSpanID)
+	}
+
+	_, err = NewChildSpan(nil, "", "predict")
+	if err == nil {
+		t.Fatalf("expected error for missing service name")
+	}
+}
+
+func TestSpanEnd_RecordsDuration(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	begin := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	elapsed := time.Since(begin)
+
+	if span.DurationNanos <= 0 {
+		t.Fatalf("expected DurationNanos > 0, got %d", span.DurationNanos)
+	}
+	if got := time.Duration(span.DurationNanos); got < 5*time.Millisecond || got > elapsed+time.Millisecond {
+		t.Fatalf("duration = %v, want between 5ms and %v", got, elapsed)
+	}
+}
+
+func TestSpanEnd_SecondCallKeepsFirstDuration(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := span.End(); err != nil {
+		t.Fatalf("first End returned error: %v", err)
+	}
+	first := span.DurationNanos
+
+	time.Sleep(2 * time.Millisecond)
+	if err := span.End(); err != ErrSpanAlreadyEnded {
+		t.Fatalf("second End error = %v, want %v", err, ErrSpanAlreadyEnded)
+	}
+	if span.DurationNanos != first {
+		t.Fatalf("duration changed from %d to %d", first, span.DurationNanos)
+	}
+}
+
+func TestSpanSetDuration_EndKeepsExplicitDuration(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithClock(clock))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.SetDuration(250 * time.Millisecond)
+	clock.Advance(time.Second)
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	if got := span.Duration(); got != 250*time.Millisecond {
+		t.Fatalf("duration = %v, want 250ms", got)
+	}
+	if !span.Ended() {
+		t.Fatalf("End did not mark the span ended")
+	}
+}
+
+func TestWithDuration_ExplicitTiming(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini",
+		WithStartTime(1_000), WithDuration(40*time.Nanosecond))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	_ = span.End()
+
+	if span.StartTimeUnixNano != 1_000 || span.DurationNanos != 40 {
+		t.Fatalf("timing = %d/%d, want 1000/40", span.StartTimeUnixNano, span.DurationNanos)
+	}
+}
+
+func TestSpanEnd_ClampsClockSkew(t *testing.T) {
+	future := time.Now().Add(time.Hour).UnixNano()
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini", WithStartTime(future))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	if span.DurationNanos != 0 {
+		t.Fatalf("duration = %d, want 0", span.DurationNanos)
+	}
+	if got, _ := span.GetAttribute(AttrClockSkewDetected); got != true {
+		t.Fatalf("%s = %v, want true", AttrClockSkewDetected, got)
+	}
+}
+
+func TestSpanEnd_NoSkewFlagForNormalSpan(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	if _, ok := span.GetAttribute(AttrClockSkewDetected); ok {
+		t.Fatalf("unexpected %s on normal span", AttrClockSkewDetected)
+	}
+}
+
+func TestSpanEnd_UsesMonotonicClock(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	// Simulate the wall clock stepping back an hour after the span started: the
+	// recorded wall start is now an hour ahead of every later wall reading.
+	span.StartTimeUnixNano += int64(time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	if got := span.Duration(); got < 5*time.Millisecond || got > time.Minute {
+		t.Fatalf("duration = %v, want the ~5ms measured on the monotonic clock", got)
+	}
+	if _, ok := span.GetAttribute(AttrClockSkewDetected); ok {
+		t.Fatalf("unexpected %s: the wall clock step should not matter", AttrClockSkewDetected)
+	}
+}
+
+func TestSpanDurationAndEndTime(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	span := &Span{
+		StartTimeUnixNano: start.UnixNano(),
+		DurationNanos:     int64(1500 * time.Millisecond),
+	}
+
+	if got, want := span.Duration(), 1500*time.Millisecond; got != want {
+		t.Fatalf("Duration() = %v, want %v", got, want)
+	}
+	if got, want := span.EndTime(), start.Add(1500*time.Millisecond); !got.Equal(want) {
+		t.Fatalf("EndTime() = %v, want %v", got, want)
+	}
+	if got := span.EndTime().Sub(time.Unix(0, span.StartTimeUnixNano)); got != span.Duration() {
+		t.Fatalf("EndTime - start = %v, want %v", got, span.Duration())
+	}
+}
+
+func TestSpanAttributes_SetOverwriteAndMissing(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.SetAttribute("gpu.id", "gpu-0")
+	span.SetAttribute("llm.prompt_tokens", 128)
+
+	if got, ok := span.GetAttribute("gpu.id"); !ok || got != "gpu-0" {
+		t.Fatalf("gpu.id = %v (ok=%v), want gpu-0", got, ok)
+	}
+	if got, ok := span.GetAttribute("llm.prompt_tokens"); !ok || got != int64(128) {
+		t.Fatalf("llm.prompt_tokens = %v (ok=%v), want 128", got, ok)
+	}
+
+	span.SetAttribute("gpu.id", "gpu-1")
+	if got, _ := span.GetAttribute("gpu.id"); got != "gpu-1" {
+		t.Fatalf("gpu.id after overwrite = %v, want gpu-1", got)
+	}
+
+	if got, ok := span.GetAttribute("missing"); ok || got != nil {
+		t.Fatalf("missing key = %v (ok=%v), want nil/false", got, ok)
+	}
+}
+
+func TestSpanTypedAttributeGetters(t *testing.T) {
+	span := &Span{}
+	_ = span.SetAttribute("s", "gpu-0")
+	_ = span.SetAttribute("i", int64(42))
+	_ = span.SetAttribute("f", 0.5)
+	_ = span.SetAttribute("b", true)
+
+	if got, ok := span.GetString("s"); !ok || got != "gpu-0" {
+		t.Fatalf("GetString = %q, %v", got, ok)
+	}
+	if got, ok := span.GetInt64("i"); !ok || got != 42 {
+		t.Fatalf("GetInt64 = %d, %v", got, ok)
+	}
+	if got, ok := span.GetFloat64("f"); !ok || got != 0.5 {
+		t.Fatalf("GetFloat64 = %v, %v", got, ok)
+	}
+	if got, ok := span.GetBool("b"); !ok || !got {
+		t.Fatalf("GetBool = %v, %v", got, ok)
+	}
+
+	if got, ok := span.GetString("missing"); ok || got != "" {
+		t.Fatalf("GetString(missing) = %q, %v; want zero, false", got, ok)
+	}
+	if got, ok := span.GetInt64("s"); ok || got != 0 {
+		t.Fatalf("GetInt64(string) = %d, %v; want zero, false", got, ok)
+	}
+	if got, ok := span.GetFloat64("i"); ok || got != 0 {
+		t.Fatalf("GetFloat64(int64) = %v, %v; want zero, false", got, ok)
+	}
+	if got, ok := span.GetBool("f"); ok || got {
+		t.Fatalf("GetBool(float64) = %v, %v; want zero, false", got, ok)
+	}
+}
+
+func TestSpanSetAttribute_InitializesNilMap(t *testing.T) {
+	span := &Span{}
+	span.SetAttribute("k", "v")
+	if got, ok := span.GetAttribute("k"); !ok || got != "v" {
+		t.Fatalf("k = %v (ok=%v), want v", got, ok)
+	}
+}
+
+func TestSpanSetAttribute_ConcurrentWriters(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			span.SetAttribute(fmt.Sprintf("key-%d", i), i)
+			_, _ = span.GetAttribute("key-0")
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range 50 {
+		key := fmt.Sprintf("key-%d", i)
+		if got, ok := span.GetAttribute(key); !ok || got != int64(i) {
+			t.Fatalf("%s = %v (ok=%v), want %d", key, got, ok, i)
+		}
+	}
+}
+
 func TestSpanValidateForIngest(t *testing.T) {
 	tests := []struct {
 		name    string
-		span    Span
+		span    *Span
 		wantErr bool
 	}{
 		{
 			name: "valid span",
-			span: Span{
+			span: &Span{
 				ServiceName:       "inference-api",
 				ModelName:         "gpt-4o-mini",
 				StartTimeUnixNano: 1,
@@ -59,7 +367,7 @@ func TestSpanValidateForIngest(t *testing.T) {
 		},
 		{
 			name: "missing service name",
-			span: Span{
+			span: &Span{
 				ModelName:         "gpt-4o-mini",
 				StartTimeUnixNano: 1,
 				DurationNanos:     10,
@@ -68,7 +376,7 @@ func TestSpanValidateForIngest(t *testing.T) {
 		},
 		{
 			name: "missing model name",
-			span: Span{
+			span: &Span{
 				ServiceName:       "inference-api",
 				StartTimeUnixNano: 1,
 				DurationNanos:     10,
@@ -77,7 +385,7 @@ func TestSpanValidateForIngest(t *testing.T) {
 		},
 		{
 			name: "missing start time",
-			span: Span{
+			span: &Span{
 				ServiceName:   "inference-api",
 				ModelName:     "gpt-4o-mini",
 				DurationNanos: 10,
@@ -86,7 +394,7 @@ func TestSpanValidateForIngest(t *testing.T) {
 		},
 		{
 			name: "missing duration",
-			span: Span{
+			span: &Span{
 				ServiceName:       "inference-api",
 				ModelName:         "gpt-4o-mini",
 				StartTimeUnixNano: 1,
@@ -107,3 +415,156 @@ func TestSpanValidateForIngest(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanValidate(t *testing.T) {
+	valid := func() *Span {
+		return &Span{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "00f067aa0ba902b7",
+			ServiceName:       "inference-api",
+			StartTimeUnixNano: 1,
+			DurationNanos:     0,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Span)
+		wantErr string
+	}{
+		{name: "valid span", mutate: func(*Span) {}},
+		{name: "missing trace id", mutate: func(s *Span) { s.TraceID = "" }, wantErr: "trace_id is required"},
+		{name: "missing service name", mutate: func(s *Span) { s.ServiceName = "" }, wantErr: "service_name is required"},
+		{name: "short trace id", mutate: func(s *Span) { s.TraceID = "abc" }, wantErr: "lowercase hex"},
+		{name: "non-hex trace id", mutate: func(s *Span) { s.TraceID = "zzf92f3577b34da6a3ce929d0e0e4736" }, wantErr: "lowercase hex"},
+		{name: "missing span id", mutate: func(s *Span) { s.SpanID = "" }, wantErr: "span_id is required"},
+		{name: "missing start time", mutate: func(s *Span) { s.StartTimeUnixNano = 0 }, wantErr: "start_time_unix_nano"},
+		{name: "negative duration", mutate: func(s *Span) { s.DurationNanos = -1 }, wantErr: "duration_nanos"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			span := valid()
+			tc.mutate(span)
+			err := span.Validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected nil error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpanValidate_ReportsAllProblems(t *testing.T) {
+	err := (&Span{DurationNanos: -1}).Validate()
+	if err == nil {
+		t.Fatalf("expected error for empty span")
+	}
+	for _, want := range []string{"trace_id", "span_id", "start_time_unix_nano", "duration_nanos"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestSpanClone_IsIndependent(t *testing.T) {
+	original, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	original.SetAttribute("gpu.id", "gpu-0")
+	original.AddEvent("model loaded", map[string]any{"shard": int64(1)})
+	original.AddLink("5bf92f3577b34da6a3ce929d0e0e4736", "10f067aa0ba902b7", map[string]any{"batch.index": int64(0)})
+
+	clone := original.Clone()
+	if clone.SpanID != original.SpanID || clone.TraceID != original.TraceID {
+		t.Fatalf("clone ids differ from original")
+	}
+
+	clone.SetAttribute("gpu.id", "gpu-9")
+	clone.SetAttribute("extra", true)
+	clone.Events[0].Attributes["shard"] = int64(2)
+	clone.Links[0].Attributes["batch.index"] = int64(5)
+	clone.AddEvent("second", nil)
+
+	if got, _ := original.GetAttribute("gpu.id"); got != "gpu-0" {
+		t.Fatalf("original gpu.id = %v, want gpu-0", got)
+	}
+	if _, ok := original.GetAttribute("extra"); ok {
+		t.Fatalf("original gained clone's attribute")
+	}
+	if got := original.Events[0].Attributes["shard"]; got != int64(1) {
+		t.Fatalf("original event attribute = %v, want 1", got)
+	}
+	if got := original.Links[0].Attributes["batch.index"]; got != int64(0) {
+		t.Fatalf("original link attribute = %v, want 0", got)
+	}
+	if len(original.Events) != 1 {
+		t.Fatalf("original event count = %d, want 1", len(original.Events))
+	}
+}
+
+func TestEndAll(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000, 0))
+	var spans []*Span
+	for range 3 {
+		span, err := NewSpan("inference-api", "generate", "gpt-4o-mini", WithClock(clock))
+		if err != nil {
+			t.Fatalf("NewSpan returned error: %v", err)
+		}
+		spans = append(spans, span)
+		clock.Advance(10 * time.Millisecond)
+	}
+	spans[1].RecordError(errors.New("already failed"))
+	if err := spans[1].End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	doneEnd := spans[1].EndTime()
+	clock.Advance(time.Second)
+
+	if got := EndAll(append(spans, nil), StatusOK); got != 2 {
+		t.Fatalf("EndAll ended %d spans, want 2", got)
+	}
+
+	if !spans[0].EndTime().Equal(spans[2].EndTime()) {
+		t.Fatalf("end times differ: %v vs %v", spans[0].EndTime(), spans[2].EndTime())
+	}
+	if want := time.Unix(1_000, 0).Add(1030 * time.Millisecond); !spans[0].EndTime().Equal(want) {
+		t.Fatalf("end time = %v, want %v", spans[0].EndTime(), want)
+	}
+	for _, i := range []int{0, 2} {
+		if !spans[i].Ended() || spans[i].Status != StatusOK {
+			t.Fatalf("span %d ended=%v status=%v, want ended with ok", i, spans[i].Ended(), spans[i].Status)
+		}
+	}
+	if spans[1].Status != StatusError || !spans[1].EndTime().Equal(doneEnd) {
+		t.Fatalf("already-ended span changed: status=%v end=%v", spans[1].Status, spans[1].EndTime())
+	}
+}
+
+func TestSpan_IsRoot(t *testing.T) {
+	root, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("NewSpan returned error: %v", err)
+	}
+	child, err := NewChildSpan(root, "tokenizer", "encode")
+	if err != nil {
+		t.Fatalf("NewChildSpan returned error: %v", err)
+	}
+	var nilSpan *Span
+
+	if !root.IsRoot() {
+		t.Fatalf("root.IsRoot() = false, want true")
+	}
+	if child.IsRoot() {
+		t.Fatalf("child.IsRoot() = true, want false")
+	}
+	if nilSpan.IsRoot() {
+		t.Fatalf("nil span IsRoot() = true, want false")
+	}
+}
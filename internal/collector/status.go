@@ -0,0 +1,143 @@
+package collector
+
+import "strings"
+
+const (
+	AttrErrorMessage   = "error.message"
+	EventNameException = "exception"
+)
+
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+func (c StatusCode) String() string {
+	switch c {
+	case StatusOK:
+		return "ok"
+	case StatusError:
+		return "error"
+	default:
+		return "unset"
+	}
+}
+
+// ParseStatusCode maps wire strings onto StatusCode case-insensitively so that
+// "ok", "OK" and "Ok" all land on StatusOK. Unknown values become StatusUnset.
+func ParseStatusCode(raw string) StatusCode {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "ok":
+		return StatusOK
+	case "error":
+		return StatusError
+	default:
+		return StatusUnset
+	}
+}
+
+func (c StatusCode) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *StatusCode) UnmarshalText(text []byte) error {
+	*c = ParseStatusCode(string(text))
+	return nil
+}
+
+func (s *Span) SetStatus(code StatusCode, description string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Status = code
+	s.StatusDescription = description
+}
+
+// RecordError marks the span as failed: it sets StatusError, stores the message
+// under AttrErrorMessage and appends an "exception" event. A nil err is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil {
+		return
+	}
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordErrorLocked(err)
+}
+
+func (s *Span) recordErrorLocked(err error) {
+	msg := err.Error()
+	s.Status = StatusError
+	s.StatusDescription = msg
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]any)
+	}
+	s.Attributes[AttrErrorMessage] = msg
+	s.Events = append(s.Events, Event{
+		Name:         EventNameException,
+		TimeUnixNano: s.now().UnixNano(),
+		Attributes:   map[string]any{"exception.message": msg},
+	})
+}
+
+// Finish records *errp with RecordError when it is non-nil, otherwise marks an
+// unset status OK, then ends the span. It is meant to be deferred with a named
+// error return, whose final value it reads:
+//
+//	func run() (err error) {
+//		defer span.Finish(&err)
+//		...
+//	}
+//
+// A nil errp counts as no error. An already ended span is left untouched and
+// ErrSpanAlreadyEnded is returned.
+func (s *Span) Finish(errp *error) error {
+	if s == nil {
+		return ErrNilSpan
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return ErrSpanAlreadyEnded
+	}
+	if errp != nil && *errp != nil {
+		s.recordErrorLocked(*errp)
+	} else if s.Status == StatusUnset {
+		s.Status = StatusOK
+	}
+	return s.endLocked(s.now())
+}
+
+// PropagateStatusTo marks parent as failed when this span failed. A parent whose
+// status is already set (OK or Error) is left alone, so an explicitly successful
+// parent is never downgraded. It reports whether parent changed.
+func (s *Span) PropagateStatusTo(parent *Span) bool {
+	if s == nil || parent == nil || parent == s {
+		return false
+	}
+
+	s.mu.Lock()
+	status, description := s.Status, s.StatusDescription
+	s.mu.Unlock()
+	if status != StatusError {
+		return false
+	}
+
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	if parent.Status != StatusUnset {
+		return false
+	}
+	parent.Status = StatusError
+	parent.StatusDescription = description
+	return true
+}
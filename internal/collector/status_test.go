@@ -0,0 +1,215 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewSpan_DefaultsToStatusUnset(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if span.Status != StatusUnset {
+		t.Fatalf("status = %v, want %v", span.Status, StatusUnset)
+	}
+}
+
+func TestSpanSetStatus_StoresCodeAndDescription(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.SetStatus(StatusError, "model timed out")
+
+	if span.Status != StatusError {
+		t.Fatalf("status = %v, want %v", span.Status, StatusError)
+	}
+	if span.StatusDescription != "model timed out" {
+		t.Fatalf("description = %q, want %q", span.StatusDescription, "model timed out")
+	}
+}
+
+func TestParseStatusCode(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want StatusCode
+	}{
+		{raw: "ok", want: StatusOK},
+		{raw: "OK", want: StatusOK},
+		{raw: "Ok", want: StatusOK},
+		{raw: "error", want: StatusError},
+		{raw: "ERROR", want: StatusError},
+		{raw: "", want: StatusUnset},
+		{raw: "bogus", want: StatusUnset},
+	}
+
+	for _, tc := range tests {
+		if got := ParseStatusCode(tc.raw); got != tc.want {
+			t.Fatalf("ParseStatusCode(%q) = %v, want %v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestSpanRecordError(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.RecordError(errors.New("CUDA out of memory"))
+
+	if span.Status != StatusError {
+		t.Fatalf("status = %v, want %v", span.Status, StatusError)
+	}
+	if got, _ := span.GetAttribute(AttrErrorMessage); got != "CUDA out of memory" {
+		t.Fatalf("%s = %v, want CUDA out of memory", AttrErrorMessage, got)
+	}
+	if len(span.Events) != 1 {
+		t.Fatalf("event count = %d, want 1", len(span.Events))
+	}
+	event := span.Events[0]
+	if event.Name != EventNameException || event.TimeUnixNano <= 0 {
+		t.Fatalf("unexpected exception event: %+v", event)
+	}
+	if got := event.Attributes["exception.message"]; got != "CUDA out of memory" {
+		t.Fatalf("exception.message = %v, want CUDA out of memory", got)
+	}
+}
+
+func TestSpanRecordError_NilIsNoop(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.RecordError(nil)
+
+	if span.Status != StatusUnset {
+		t.Fatalf("status = %v, want %v", span.Status, StatusUnset)
+	}
+	if _, ok := span.GetAttribute(AttrErrorMessage); ok {
+		t.Fatalf("expected no %s attribute", AttrErrorMessage)
+	}
+	if span.Events != nil {
+		t.Fatalf("expected no events, got %v", span.Events)
+	}
+}
+
+func TestSpanPropagateStatusTo_FailingChildFlipsParent(t *testing.T) {
+	parent, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	child, err := NewChildSpan(parent, "tokenizer", "encode")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	child.RecordError(errors.New("invalid utf-8"))
+	if !child.PropagateStatusTo(parent) {
+		t.Fatalf("expected parent to change")
+	}
+	if parent.Status != StatusError || parent.StatusDescription != "invalid utf-8" {
+		t.Fatalf("parent status = %v %q, want error %q", parent.Status, parent.StatusDescription, "invalid utf-8")
+	}
+}
+
+func TestSpanPropagateStatusTo_LeavesSetParentAlone(t *testing.T) {
+	parent, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	parent.SetStatus(StatusOK, "")
+
+	failing, _ := NewChildSpan(parent, "tokenizer", "encode")
+	failing.SetStatus(StatusError, "boom")
+	if failing.PropagateStatusTo(parent) {
+		t.Fatalf("expected OK parent to be left alone")
+	}
+	if parent.Status != StatusOK {
+		t.Fatalf("parent status = %v, want %v", parent.Status, StatusOK)
+	}
+
+	unset, _ := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	ok, _ := NewChildSpan(unset, "tokenizer", "encode")
+	ok.SetStatus(StatusOK, "")
+	if ok.PropagateStatusTo(unset) || unset.Status != StatusUnset {
+		t.Fatalf("successful child must not change parent, got %v", unset.Status)
+	}
+}
+
+func TestSpanFinish(t *testing.T) {
+	run := func(span *Span, fail error) (err error) {
+		defer span.Finish(&err)
+		return fail
+	}
+
+	t.Run("error", func(t *testing.T) {
+		span, _ := NewSpan("inference-api", "predict", "gpt-4o-mini")
+		_ = run(span, errors.New("CUDA out of memory"))
+
+		if !span.Ended() {
+			t.Fatalf("span not ended")
+		}
+		if span.Status != StatusError || span.StatusDescription != "CUDA out of memory" {
+			t.Fatalf("status = %v %q, want %v with the error message", span.Status, span.StatusDescription, StatusError)
+		}
+		if got, _ := span.GetAttribute(AttrErrorMessage); got != "CUDA out of memory" {
+			t.Fatalf("%s = %v, want CUDA out of memory", AttrErrorMessage, got)
+		}
+		if len(span.Events) != 1 || span.Events[0].Name != EventNameException {
+			t.Fatalf("events = %+v, want one exception event", span.Events)
+		}
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		span, _ := NewSpan("inference-api", "predict", "gpt-4o-mini")
+		_ = run(span, nil)
+
+		if !span.Ended() {
+			t.Fatalf("span not ended")
+		}
+		if span.Status != StatusOK {
+			t.Fatalf("status = %v, want %v", span.Status, StatusOK)
+		}
+		if _, ok := span.GetAttribute(AttrErrorMessage); ok || len(span.Events) != 0 {
+			t.Fatalf("error recorded on successful span: %v, %v", span.Attributes, span.Events)
+		}
+	})
+
+	t.Run("nil pointer keeps explicit status", func(t *testing.T) {
+		span, _ := NewSpan("inference-api", "predict", "gpt-4o-mini")
+		span.SetStatus(StatusError, "rejected")
+		if err := span.Finish(nil); err != nil {
+			t.Fatalf("Finish returned error: %v", err)
+		}
+		if span.Status != StatusError || !span.Ended() {
+			t.Fatalf("status = %v, ended = %v, want %v and ended", span.Status, span.Ended(), StatusError)
+		}
+		if err := span.Finish(nil); !errors.Is(err, ErrSpanAlreadyEnded) {
+			t.Fatalf("second Finish = %v, want %v", err, ErrSpanAlreadyEnded)
+		}
+	})
+
+	t.Run("already ended span is unchanged", func(t *testing.T) {
+		for _, fail := range []error{nil, errors.New("late failure")} {
+			span, _ := NewSpan("inference-api", "predict", "gpt-4o-mini")
+			if err := span.End(); err != nil {
+				t.Fatalf("End returned error: %v", err)
+			}
+			duration := span.Duration()
+
+			if err := span.Finish(&fail); !errors.Is(err, ErrSpanAlreadyEnded) {
+				t.Fatalf("Finish after End = %v, want %v", err, ErrSpanAlreadyEnded)
+			}
+			if span.Status != StatusUnset || span.StatusDescription != "" {
+				t.Fatalf("status = %v %q, want unchanged %v", span.Status, span.StatusDescription, StatusUnset)
+			}
+			if len(span.Events) != 0 || len(span.Attributes) != 0 || span.Duration() != duration {
+				t.Fatalf("ended span changed: events %v, attributes %v", span.Events, span.Attributes)
+			}
+		}
+	})
+}
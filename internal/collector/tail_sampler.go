@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+type SampleDecision int
+
+const (
+	SamplePending SampleDecision = iota
+	SampleKeep
+	SampleDrop
+)
+
+func (d SampleDecision) String() string {
+	switch d {
+	case SampleKeep:
+		return "keep"
+	case SampleDrop:
+		return "drop"
+	default:
+		return "pending"
+	}
+}
+
+// TailSampler buffers each trace until it is complete or has been waiting for
+// timeout, then decides once for the whole trace: traces containing an error
+// are always kept, the rest go through sampler.
+type TailSampler struct {
+	sampler    Sampler
+	timeout    time.Duration
+	onDecision func(traceID string, decision SampleDecision, spans []*Span)
+	clock      Clock
+	decisions  *DecisionCache
+
+	buffer *SpanStore
+
+	mu        sync.Mutex
+	firstSeen map[string]time.Time
+}
+
+type TailSamplerOption func(*TailSampler)
+
+// WithDecisionCache remembers up to size decided traces, so Decide answers
+// repeat lookups without re-deciding and spans arriving after the decision are
+// passed straight to onDecision with the cached outcome.
+func WithDecisionCache(size int) TailSamplerOption {
+	return func(t *TailSampler) {
+		t.decisions = NewDecisionCache(size)
+	}
+}
+
+// NewTailSampler buffers traces for at most timeout before deciding. onDecision,
+// if non-nil, receives every decided trace with its spans; the trace is then
+// dropped from the buffer.
+func NewTailSampler(
+	sampler Sampler,
+	timeout time.Duration,
+	onDecision func(traceID string, decision SampleDecision, spans []*Span),
+	opts ...TailSamplerOption,
+) *TailSampler {
+	if sampler == nil {
+		sampler = NewProbabilitySampler(1)
+	}
+	if onDecision == nil {
+		onDecision = func(string, SampleDecision, []*Span) {}
+	}
+	t := &TailSampler{
+		sampler:    sampler,
+		timeout:    timeout,
+		onDecision: onDecision,
+		clock:      realClock{},
+		buffer:     NewSpanStore(),
+		firstSeen:  make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *TailSampler) Add(span *Span) {
+	if span == nil {
+		return
+	}
+	if t.decisions != nil {
+		if decision, ok := t.decisions.Get(span.TraceID); ok {
+			t.onDecision(span.TraceID, decision, []*Span{span})
+			return
+		}
+	}
+
+	// Buffer under t.mu so Decide cannot take the trace between the firstSeen
+	// check and the Add and lose this span.
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.firstSeen[span.TraceID]; !ok {
+		t.firstSeen[span.TraceID] = t.clock.Now()
+	}
+	t.buffer.Add(span)
+}
+
+// Decide returns SamplePending while the trace is still incomplete and younger
+// than the timeout. Otherwise it makes the final decision, reports it through
+// onDecision and evicts the trace.
+func (t *TailSampler) Decide(traceID string) SampleDecision {
+	if t.decisions != nil {
+		if decision, ok := t.decisions.Get(traceID); ok {
+			return decision
+		}
+	}
+
+	t.mu.Lock()
+	seen, ok := t.firstSeen[traceID]
+	if !ok {
+		t.mu.Unlock()
+		return SamplePending
+	}
+	timedOut := t.clock.Now().Sub(seen) >= t.timeout
+	if !timedOut && !t.buffer.IsTraceComplete(traceID) {
+		t.mu.Unlock()
+		return SamplePending
+	}
+	delete(t.firstSeen, traceID)
+	spans := t.buffer.take(traceID)
+	t.mu.Unlock()
+
+	decision := SampleDrop
+	if traceHasError(spans) || t.sampler.ShouldSample(traceID) {
+		decision = SampleKeep
+	}
+	if t.decisions != nil {
+		t.decisions.Put(traceID, decision)
+	}
+	t.onDecision(traceID, decision, spans)
+	return decision
+}
+
+func traceHasError(spans []*Span) bool {
+	for _, span := range spans {
+		span.mu.Lock()
+		status := span.Status
+		span.mu.Unlock()
+		if status == StatusError {
+			return true
+		}
+	}
+	return false
+}
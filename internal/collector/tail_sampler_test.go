@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTailSampler_KeepsErrorTraceEvenAtZeroRate(t *testing.T) {
+	var decided []*Span
+	sampler := NewTailSampler(NewProbabilitySampler(0), time.Minute, func(_ string, _ SampleDecision, spans []*Span) {
+		decided = spans
+	})
+
+	traceID := GenerateTraceID()
+	sampler.Add(&Span{TraceID: traceID, SpanID: "b", ParentSpanID: "a", Status: StatusError})
+	if got := sampler.Decide(traceID); got != SamplePending {
+		t.Fatalf("decision before root arrives = %v, want pending", got)
+	}
+
+	sampler.Add(&Span{TraceID: traceID, SpanID: "a"})
+	if got := sampler.Decide(traceID); got != SampleKeep {
+		t.Fatalf("decision = %v, want keep", got)
+	}
+	if len(decided) != 2 {
+		t.Fatalf("callback received %d spans, want 2", len(decided))
+	}
+	if _, ok := sampler.buffer.GetTrace(traceID); ok {
+		t.Fatalf("expected decided trace to be evicted from buffer")
+	}
+}
+
+func TestTailSampler_CleanTraceUsesProbability(t *testing.T) {
+	never := NewTailSampler(NewProbabilitySampler(0), time.Minute, nil)
+	always := NewTailSampler(NewProbabilitySampler(1), time.Minute, nil)
+
+	for _, tc := range []struct {
+		sampler *TailSampler
+		want    SampleDecision
+	}{
+		{sampler: never, want: SampleDrop},
+		{sampler: always, want: SampleKeep},
+	} {
+		traceID := GenerateTraceID()
+		tc.sampler.Add(&Span{TraceID: traceID, SpanID: "a", Status: StatusOK})
+		if got := tc.sampler.Decide(traceID); got != tc.want {
+			t.Fatalf("decision = %v, want %v", got, tc.want)
+		}
+	}
+}
+
+func TestTailSampler_DecidesIncompleteTraceAfterTimeout(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000, 0))
+	sampler := NewTailSampler(NewProbabilitySampler(0), 30*time.Second, nil)
+	sampler.clock = clock
+
+	traceID := GenerateTraceID()
+	sampler.Add(&Span{TraceID: traceID, SpanID: "b", ParentSpanID: "missing", Status: StatusError})
+
+	if got := sampler.Decide(traceID); got != SamplePending {
+		t.Fatalf("decision before timeout = %v, want pending", got)
+	}
+	clock.Advance(30 * time.Second)
+	if got := sampler.Decide(traceID); got != SampleKeep {
+		t.Fatalf("decision after timeout = %v, want keep", got)
+	}
+}
+
+func TestTailSampler_ConcurrentAddIsNeverLost(t *testing.T) {
+	var mu sync.Mutex
+	decided := 0
+	sampler := NewTailSampler(NewProbabilitySampler(1), 0, func(_ string, _ SampleDecision, spans []*Span) {
+		mu.Lock()
+		decided += len(spans)
+		mu.Unlock()
+	})
+
+	const spans = 2000
+	traceID := GenerateTraceID()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range spans {
+			sampler.Add(&Span{TraceID: traceID, SpanID: fmt.Sprintf("s%d", i), ParentSpanID: "missing"})
+		}
+	}()
+	var deciders sync.WaitGroup
+	for range 4 {
+		deciders.Add(1)
+		go func() {
+			defer deciders.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					sampler.Decide(traceID)
+				}
+			}
+		}()
+	}
+	deciders.Wait()
+	sampler.Decide(traceID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if decided != spans {
+		t.Fatalf("decided %d spans, want %d", decided, spans)
+	}
+}
@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	timelineWidth  = 40
+	timelineMarker = "*"
+)
+
+// RenderTimeline prints a trace as an indented timeline for terminal
+// debugging: one line per span with its "operation (service)" label, start
+// offset from the trace start, a bar scaled to the trace's total width and the
+// duration. Children are ordered by start time. A span without a duration is
+// drawn as a single marker. The spans must form a tree, as for BuildTree.
+func RenderTimeline(w io.Writer, spans []*Span) error {
+	root, err := BuildTree(spans)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		label    string
+		start    int64
+		duration int64
+	}
+	var rows []row
+	var walk func(node *SpanNode, depth int)
+	walk = func(node *SpanNode, depth int) {
+		span := node.Span
+		span.mu.Lock()
+		rows = append(rows, row{
+			label:    strings.Repeat("  ", depth) + span.OperationName + " (" + span.ServiceName + ")",
+			start:    span.StartTimeUnixNano,
+			duration: span.DurationNanos,
+		})
+		span.mu.Unlock()
+
+		children := append([]*SpanNode(nil), node.Children...)
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].Span.StartTimeUnixNano < children[j].Span.StartTimeUnixNano
+		})
+		for _, child := range children {
+			walk(child, depth+1)
+		}
+	}
+	walk(root, 0)
+
+	traceStart, traceEnd := rows[0].start, rows[0].start+rows[0].duration
+	labelWidth := 0
+	for _, r := range rows {
+		traceStart = min(traceStart, r.start)
+		traceEnd = max(traceEnd, r.start+r.duration)
+		labelWidth = max(labelWidth, len(r.label))
+	}
+	total := max(traceEnd-traceStart, 1)
+
+	for _, r := range rows {
+		offset := r.start - traceStart
+		pos := min(int(offset*timelineWidth/total), timelineWidth-1)
+		bar := timelineMarker
+		duration := "-"
+		if r.duration > 0 {
+			bar = strings.Repeat("=", max(int(r.duration*timelineWidth/total), 1))
+			duration = time.Duration(r.duration).String()
+		}
+		cells := strings.Repeat(" ", pos) + bar
+		cells += strings.Repeat(" ", max(timelineWidth-len(cells), 0))
+		if _, err := fmt.Fprintf(w, "%-*s  %-8s |%s| %s\n", labelWidth, r.label, "+"+time.Duration(offset).String(), cells, duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
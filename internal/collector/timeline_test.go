@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTimeline(t *testing.T) {
+	ms := int64(time.Millisecond)
+	spans := []*Span{
+		{SpanID: "root", ServiceName: "api", OperationName: "predict", StartTimeUnixNano: 1000 * ms, DurationNanos: 100 * ms},
+		{SpanID: "gen", ParentSpanID: "root", ServiceName: "model", OperationName: "generate", StartTimeUnixNano: 1050 * ms, DurationNanos: 50 * ms},
+		{SpanID: "tok", ParentSpanID: "root", ServiceName: "tokenizer", OperationName: "encode", StartTimeUnixNano: 1000 * ms, DurationNanos: 10 * ms},
+		{SpanID: "cache", ParentSpanID: "gen", ServiceName: "model", OperationName: "cache", StartTimeUnixNano: 1075 * ms},
+	}
+
+	var out strings.Builder
+	if err := RenderTimeline(&out, spans); err != nil {
+		t.Fatalf("RenderTimeline returned error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"predict (api)         +0s      |" + strings.Repeat("=", 40) + "| 100ms",
+		"  encode (tokenizer)  +0s      |====" + strings.Repeat(" ", 36) + "| 10ms",
+		"  generate (model)    +50ms    |" + strings.Repeat(" ", 20) + strings.Repeat("=", 20) + "| 50ms",
+		"    cache (model)     +75ms    |" + strings.Repeat(" ", 30) + "*" + strings.Repeat(" ", 9) + "| -",
+		"",
+	}, "\n")
+	if got := out.String(); got != want {
+		t.Fatalf("RenderTimeline output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderTimeline_InvalidTree(t *testing.T) {
+	var out strings.Builder
+	err := RenderTimeline(&out, []*Span{{SpanID: "a", ParentSpanID: "missing"}})
+	if !errors.Is(err, ErrNoRootSpan) {
+		t.Fatalf("RenderTimeline error = %v, want %v", err, ErrNoRootSpan)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("wrote output for an invalid trace: %q", out.String())
+	}
+}
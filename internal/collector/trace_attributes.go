@@ -0,0 +1,59 @@
+package collector
+
+import "maps"
+
+// SetTraceAttribute records an attribute for the whole trace. It is stored
+// once, apart from span Attributes, and dropped along with the trace. The
+// trace does not need to have any spans yet; setting an attribute counts as
+// activity for the idle reaper, which also evicts traces that never get one.
+func (s *SpanStore) SetTraceAttribute(traceID, key string, value any) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attrs := s.traceAttrs[traceID]
+	if attrs == nil {
+		attrs = make(map[string]any)
+		s.traceAttrs[traceID] = attrs
+	}
+	attrs[key] = value
+	s.lastUpdated[traceID] = now
+}
+
+// TraceAttributes returns a copy of the trace's attributes, or nil if none
+// were set.
+func (s *SpanStore) TraceAttributes(traceID string) map[string]any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.traceAttrs[traceID])
+}
+
+// FoldTraceAttributes returns spans with attrs copied onto the root span, for
+// exporters whose backends have no notion of trace-level attributes. The root
+// is replaced by a clone so stored spans are untouched, and attributes already
+// on the root win. Spans are returned unchanged if there is no root.
+func FoldTraceAttributes(spans []*Span, attrs map[string]any) []*Span {
+	if len(attrs) == 0 {
+		return spans
+	}
+	out := make([]*Span, len(spans))
+	copy(out, spans)
+	for i, span := range out {
+		if !span.IsRoot() {
+			continue
+		}
+		root := span.Clone()
+		if root.Attributes == nil {
+			root.Attributes = make(map[string]any, len(attrs))
+		}
+		for key, value := range attrs {
+			if _, ok := root.Attributes[key]; !ok {
+				root.Attributes[key] = value
+			}
+		}
+		out[i] = root
+	}
+	return out
+}
@@ -0,0 +1,135 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSpanStore_TraceAttributes(t *testing.T) {
+	store := NewSpanStore()
+	store.Add(&Span{TraceID: "t1", SpanID: "root"})
+	store.Add(&Span{TraceID: "t1", SpanID: "child", ParentSpanID: "root"})
+
+	store.SetTraceAttribute("t1", "tenant", "acme")
+	store.SetTraceAttribute("t1", "experiment", "b")
+
+	attrs := store.TraceAttributes("t1")
+	if len(attrs) != 2 || attrs["tenant"] != "acme" || attrs["experiment"] != "b" {
+		t.Fatalf("TraceAttributes = %v, want tenant and experiment", attrs)
+	}
+
+	attrs["tenant"] = "mutated"
+	if got := store.TraceAttributes("t1")["tenant"]; got != "acme" {
+		t.Fatalf("tenant after mutating copy = %v, want acme", got)
+	}
+
+	spans, _ := store.GetTrace("t1")
+	for _, span := range spans {
+		if _, ok := span.Attributes["tenant"]; ok {
+			t.Fatalf("span %s has trace attribute tenant", span.SpanID)
+		}
+	}
+
+	if got := store.TraceAttributes("other"); got != nil {
+		t.Fatalf("TraceAttributes(other) = %v, want nil", got)
+	}
+}
+
+func TestSpanStore_TraceAttributesDeletedWithTrace(t *testing.T) {
+	store := NewSpanStore()
+	store.Add(&Span{TraceID: "t1", SpanID: "root"})
+	store.SetTraceAttribute("t1", "tenant", "acme")
+
+	store.delete("t1")
+
+	if got := store.TraceAttributes("t1"); got != nil {
+		t.Fatalf("TraceAttributes after delete = %v, want nil", got)
+	}
+}
+
+func TestFoldTraceAttributes(t *testing.T) {
+	root := &Span{TraceID: "t1", SpanID: "root", Attributes: map[string]any{"tenant": "own"}}
+	child := &Span{TraceID: "t1", SpanID: "child", ParentSpanID: "root"}
+	spans := []*Span{child, root}
+
+	folded := FoldTraceAttributes(spans, map[string]any{"tenant": "acme", "region": "us"})
+
+	if folded[0] != child {
+		t.Fatalf("child was replaced, want it passed through")
+	}
+	if _, ok := folded[0].Attributes["region"]; ok {
+		t.Fatalf("child has region, want trace attributes on the root only")
+	}
+	gotRoot := folded[1]
+	if gotRoot == root {
+		t.Fatalf("root was not cloned")
+	}
+	if gotRoot.Attributes["region"] != "us" {
+		t.Fatalf("root region = %v, want us", gotRoot.Attributes["region"])
+	}
+	if gotRoot.Attributes["tenant"] != "own" {
+		t.Fatalf("root tenant = %v, want own", gotRoot.Attributes["tenant"])
+	}
+	if _, ok := root.Attributes["region"]; ok {
+		t.Fatalf("original root was modified")
+	}
+	if spans[1] != root {
+		t.Fatalf("input slice was modified")
+	}
+}
+
+func TestFoldTraceAttributes_NoAttrs(t *testing.T) {
+	spans := []*Span{{TraceID: "t1", SpanID: "root"}}
+	if got := FoldTraceAttributes(spans, nil); got[0] != spans[0] {
+		t.Fatalf("FoldTraceAttributes(nil) replaced the root")
+	}
+}
+
+func TestSpanStore_EvictIdleDropsOrphanTraceAttributes(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	store := NewSpanStore(WithStoreClock(clock))
+	store.SetTraceAttribute("never-sent", "tenant", "acme")
+
+	clock.Advance(time.Minute)
+	if got := store.EvictIdle(time.Minute); len(got) != 0 {
+		t.Fatalf("EvictIdle returned %d traces, want none for attribute-only trace", len(got))
+	}
+	if got := store.TraceAttributes("never-sent"); got != nil {
+		t.Fatalf("TraceAttributes after eviction = %v, want nil", got)
+	}
+}
+
+func TestReaper_FoldsTraceAttributesOntoRoot(t *testing.T) {
+	for _, fold := range []bool{false, true} {
+		clock := newFakeClock(time.Unix(1700000000, 0))
+		store := NewSpanStore(WithStoreClock(clock))
+		exporter := &recordingExporter{}
+		var opts []ReaperOption
+		if fold {
+			opts = append(opts, WithFoldTraceAttributes())
+		}
+		reaper := NewReaper(store, exporter, time.Minute, time.Hour, opts...)
+
+		store.Add(&Span{TraceID: "t1", SpanID: "root"})
+		store.Add(&Span{TraceID: "t1", SpanID: "child", ParentSpanID: "root"})
+		store.SetTraceAttribute("t1", "tenant", "acme")
+		clock.Advance(time.Minute)
+		if err := reaper.Reap(context.Background()); err != nil {
+			t.Fatalf("Reap returned error: %v", err)
+		}
+		reaper.Stop()
+
+		batch := exporter.Batches()[0]
+		byID := map[string]*Span{}
+		for _, span := range batch {
+			byID[span.SpanID] = span
+		}
+		if got, _ := byID["root"].GetString("tenant"); (got == "acme") != fold {
+			t.Fatalf("fold=%v: root tenant = %q", fold, got)
+		}
+		if _, ok := byID["child"].GetAttribute("tenant"); ok {
+			t.Fatalf("fold=%v: child has trace attribute tenant", fold)
+		}
+	}
+}
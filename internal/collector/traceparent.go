@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	traceIDHexLen  = 32
+	spanIDHexLen   = 16
+	flagSampled    = 0x01
+	traceparentVer = "00"
+)
+
+type Traceparent struct {
+	TraceID      string
+	ParentSpanID string
+	Flags        byte
+}
+
+// ParseTraceparent parses a W3C traceparent header of the form
+// version-traceid-parentid-flags, e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func ParseTraceparent(header string) (Traceparent, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 {
+		return Traceparent{}, fmt.Errorf("%w: must have 4 fields, got %d", ErrInvalidTraceparent, len(parts))
+	}
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+
+	if !isLowerHex(version, 2) {
+		return Traceparent{}, fmt.Errorf("%w: version %q must be 2 lowercase hex characters", ErrInvalidTraceparent, version)
+	}
+	if version != traceparentVer {
+		return Traceparent{}, fmt.Errorf("%w: unsupported version %q", ErrInvalidTraceparent, version)
+	}
+	if !isLowerHex(traceID, traceIDHexLen) {
+		return Traceparent{}, fmt.Errorf("%w: trace-id %q must be %d lowercase hex characters", ErrInvalidTraceparent, traceID, traceIDHexLen)
+	}
+	if isAllZeros(traceID) {
+		return Traceparent{}, fmt.Errorf("%w: trace-id must not be all zeros", ErrInvalidTraceparent)
+	}
+	if !isLowerHex(parentID, spanIDHexLen) {
+		return Traceparent{}, fmt.Errorf("%w: parent-id %q must be %d lowercase hex characters", ErrInvalidTraceparent, parentID, spanIDHexLen)
+	}
+	if isAllZeros(parentID) {
+		return Traceparent{}, fmt.Errorf("%w: parent-id must not be all zeros", ErrInvalidTraceparent)
+	}
+	if !isLowerHex(flags, 2) {
+		return Traceparent{}, fmt.Errorf("%w: trace-flags %q must be 2 lowercase hex characters", ErrInvalidTraceparent, flags)
+	}
+
+	flagBytes, _ := hex.DecodeString(flags)
+	return Traceparent{
+		TraceID:      traceID,
+		ParentSpanID: parentID,
+		Flags:        flagBytes[0],
+	}, nil
+}
+
+func (tp Traceparent) Sampled() bool {
+	return tp.Flags&flagSampled != 0
+}
+
+// NewSpanFromTraceparent starts a span that continues the caller's trace at an
+// RPC boundary: TraceID and ParentSpanID come from header and the SpanID is
+// fresh. An empty header starts a new root trace; a malformed one is an error
+// wrapping ErrInvalidTraceparent.
+func NewSpanFromTraceparent(header, serviceName, operationName string, opts ...SpanOption) (*Span, error) {
+	span := newSpan(serviceName, operationName, "")
+	if span.ServiceName == "" {
+		return nil, ErrEmptyServiceName
+	}
+	if span.OperationName == "" {
+		return nil, ErrEmptyOperationName
+	}
+	if err := span.continueTrace(header); err != nil {
+		return nil, err
+	}
+	applySpanOptions(span, opts)
+	return span, nil
+}
+
+// continueTrace copies the trace context from a traceparent header onto a span
+// that has not been through applySpanOptions yet. An empty header is a no-op.
+func (s *Span) continueTrace(header string) error {
+	if strings.TrimSpace(header) == "" {
+		return nil
+	}
+	tp, err := ParseTraceparent(header)
+	if err != nil {
+		return err
+	}
+	s.TraceID = tp.TraceID
+	s.ParentSpanID = tp.ParentSpanID
+	s.Sampled = tp.Sampled()
+	return nil
+}
+
+// String renders tp in the canonical form accepted by ParseTraceparent.
+func (tp Traceparent) String() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVer, tp.TraceID, tp.ParentSpanID, tp.Flags)
+}
+
+// FormatTraceparent builds the traceparent header for an outbound call made on
+// behalf of span, so the callee's spans become its children. The sampled flag
+// mirrors span.Sampled. A nil span yields "", meaning no header.
+func FormatTraceparent(span *Span) string {
+	if span == nil {
+		return ""
+	}
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	tp := Traceparent{
+		TraceID:      span.TraceID,
+		ParentSpanID: span.SpanID,
+	}
+	if span.Sampled {
+		tp.Flags = flagSampled
+	}
+	return tp.String()
+}
+
+func isLowerHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAllZeros(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTraceparent_Valid(t *testing.T) {
+	tp, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if tp.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace id = %q", tp.TraceID)
+	}
+	if tp.ParentSpanID != "00f067aa0ba902b7" {
+		t.Fatalf("parent span id = %q", tp.ParentSpanID)
+	}
+	if !tp.Sampled() {
+		t.Fatalf("expected sampled flag to be set")
+	}
+}
+
+func TestParseTraceparent_Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "empty", header: ""},
+		{name: "too few fields", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7"},
+		{name: "too many fields", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "unsupported version", header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "non-hex version", header: "zz-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "short trace id", header: "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01"},
+		{name: "non-hex trace id", header: "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01"},
+		{name: "uppercase trace id", header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{name: "all-zero trace id", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "non-hex parent id", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902zz-01"},
+		{name: "all-zero parent id", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{name: "non-hex flags", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0g"},
+		{name: "long flags", header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-001"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTraceparent(tc.header)
+			if !errors.Is(err, ErrInvalidTraceparent) {
+				t.Fatalf("ParseTraceparent(%q) error = %v, want %v", tc.header, err, ErrInvalidTraceparent)
+			}
+		})
+	}
+}
+
+func TestTraceparent_RoundTrip(t *testing.T) {
+	headers := []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-03",
+	}
+	for _, header := range headers {
+		tp, err := ParseTraceparent(header)
+		if err != nil {
+			t.Fatalf("ParseTraceparent(%q) error: %v", header, err)
+		}
+		if got := tp.String(); got != header {
+			t.Fatalf("String() = %q, want %q", got, header)
+		}
+	}
+}
+
+func TestFormatTraceparent(t *testing.T) {
+	tests := []struct {
+		sampled bool
+		want    string
+	}{
+		{sampled: true, want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{sampled: false, want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+	}
+	for _, tt := range tests {
+		span := &Span{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: tt.sampled}
+		got := FormatTraceparent(span)
+		if got != tt.want {
+			t.Fatalf("FormatTraceparent(sampled=%v) = %q, want %q", tt.sampled, got, tt.want)
+		}
+		tp, err := ParseTraceparent(got)
+		if err != nil {
+			t.Fatalf("ParseTraceparent(%q) error: %v", got, err)
+		}
+		if tp.Sampled() != tt.sampled {
+			t.Fatalf("Sampled() = %v, want %v", tp.Sampled(), tt.sampled)
+		}
+	}
+}
+
+func TestFormatTraceparent_NilSpan(t *testing.T) {
+	if got := FormatTraceparent(nil); got != "" {
+		t.Fatalf("FormatTraceparent(nil) = %q, want empty", got)
+	}
+}
+
+func TestNewSpanFromTraceparent(t *testing.T) {
+	span, err := NewSpanFromTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", "api", "predict")
+	if err != nil {
+		t.Fatalf("NewSpanFromTraceparent returned error: %v", err)
+	}
+	if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || span.ParentSpanID != "00f067aa0ba902b7" {
+		t.Fatalf("ids = %s parent %s", span.TraceID, span.ParentSpanID)
+	}
+	if len(span.SpanID) != 16 || span.SpanID == span.ParentSpanID {
+		t.Fatalf("SpanID = %q, want a fresh 16-hex ID", span.SpanID)
+	}
+	if span.Sampled {
+		t.Fatalf("Sampled = true, want flag from header (00)")
+	}
+}
+
+func TestNewSpanFromTraceparent_Malformed(t *testing.T) {
+	span, err := NewSpanFromTraceparent("00-nothex-00f067aa0ba902b7-01", "api", "predict")
+	if !errors.Is(err, ErrInvalidTraceparent) || span != nil {
+		t.Fatalf("NewSpanFromTraceparent = %v, %v; want nil, %v", span, err, ErrInvalidTraceparent)
+	}
+}
+
+func TestNewSpanFromTraceparent_EmptyStartsRoot(t *testing.T) {
+	span, err := NewSpanFromTraceparent("", "api", "predict")
+	if err != nil {
+		t.Fatalf("NewSpanFromTraceparent returned error: %v", err)
+	}
+	if span.ParentSpanID != "" || len(span.TraceID) != 32 || !span.Sampled {
+		t.Fatalf("expected sampled root span, got trace=%q parent=%q sampled=%v", span.TraceID, span.ParentSpanID, span.Sampled)
+	}
+}
@@ -0,0 +1,59 @@
+package collector
+
+// Tracer holds the configuration shared by every span a service creates, so
+// call sites only pass what differs per span.
+type Tracer struct {
+	serviceName string
+	sampler     Sampler
+	ids         IDGenerator
+	clock       Clock
+	resource    Resource
+}
+
+type TracerOption func(*Tracer)
+
+func WithTracerSampler(sampler Sampler) TracerOption {
+	return func(t *Tracer) {
+		t.sampler = sampler
+	}
+}
+
+func WithTracerIDGenerator(ids IDGenerator) TracerOption {
+	return func(t *Tracer) {
+		t.ids = ids
+	}
+}
+
+func WithTracerClock(clock Clock) TracerOption {
+	return func(t *Tracer) {
+		t.clock = clock
+	}
+}
+
+// WithTracerResource attaches res to every span the tracer starts.
+func WithTracerResource(res Resource) TracerOption {
+	return func(t *Tracer) {
+		t.resource = res
+	}
+}
+
+func NewTracer(serviceName string, opts ...TracerOption) *Tracer {
+	t := &Tracer{serviceName: serviceName}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// StartSpan starts a span named name in the tracer's service. opts run after the
+// tracer's defaults, so WithClock or WithParent still apply per span.
+func (t *Tracer) StartSpan(name string, opts ...SpanOption) *Span {
+	span := newSpan(t.serviceName, name, "")
+	span.sampler = t.sampler
+	span.ids = t.ids
+	span.clock = t.clock
+	span.Resource = t.resource
+
+	applySpanOptions(span, opts)
+	return span
+}
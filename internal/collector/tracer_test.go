@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracer_StartSpanUsesSharedConfig(t *testing.T) {
+	clock := newFakeClock(time.Unix(1700000000, 0))
+	resource := Resource{"host.name": "gpu-node-7"}
+	tracer := NewTracer("inference-api",
+		WithTracerClock(clock),
+		WithTracerIDGenerator(&SequentialIDGenerator{}),
+		WithTracerResource(resource),
+	)
+
+	root := tracer.StartSpan("predict")
+	clock.Advance(5 * time.Millisecond)
+	child := tracer.StartSpan("tokenize", WithParent(root))
+	clock.Advance(5 * time.Millisecond)
+	_ = child.End()
+	_ = root.End()
+
+	if root.TraceID != "00000000000000000000000000000001" || root.SpanID != "0000000000000001" {
+		t.Fatalf("root ids = %s/%s, want sequential", root.TraceID, root.SpanID)
+	}
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID || child.SpanID != "0000000000000002" {
+		t.Fatalf("child ids = %s/%s parent %s", child.TraceID, child.SpanID, child.ParentSpanID)
+	}
+	if root.ServiceName != "inference-api" || root.OperationName != "predict" {
+		t.Fatalf("root names = %q/%q", root.ServiceName, root.OperationName)
+	}
+	if root.StartTimeUnixNano != time.Unix(1700000000, 0).UnixNano() {
+		t.Fatalf("root start = %d, want fake clock time", root.StartTimeUnixNano)
+	}
+	if root.Duration() != 10*time.Millisecond || child.Duration() != 5*time.Millisecond {
+		t.Fatalf("durations = %v/%v, want 10ms/5ms", root.Duration(), child.Duration())
+	}
+	if root.Resource["host.name"] != "gpu-node-7" || child.Resource["host.name"] != "gpu-node-7" {
+		t.Fatalf("tracer resource not attached")
+	}
+}
+
+func TestTracer_SamplerDecidesRootsOnly(t *testing.T) {
+	tracer := NewTracer("api", WithTracerSampler(NewProbabilitySampler(0)))
+
+	root := tracer.StartSpan("predict")
+	if root.Sampled {
+		t.Fatalf("root Sampled = true with rate 0")
+	}
+
+	parent := NewTracer("upstream").StartSpan("call")
+	child := tracer.StartSpan("handle", WithParent(parent))
+	if !child.Sampled {
+		t.Fatalf("child of sampled parent was dropped by the tracer's sampler")
+	}
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoRootSpan        = errors.New("trace has no root span")
+	ErrMultipleRootSpans = errors.New("trace has more than one root span")
+)
+
+type SpanNode struct {
+	Span     *Span
+	Children []*SpanNode
+	// Orphaned is set when the span's parent was not in the input and the node
+	// was attached to the root instead.
+	Orphaned bool
+}
+
+// BuildTree links spans into a hierarchy by ParentSpanID. Exactly one span must
+// have an empty ParentSpanID. Spans whose parent is missing are attached to the
+// root and marked Orphaned. Children keep their input order.
+func BuildTree(spans []*Span) (*SpanNode, error) {
+	nodes := make(map[string]*SpanNode, len(spans))
+	ordered := make([]*SpanNode, 0, len(spans))
+	var root *SpanNode
+
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		node := &SpanNode{Span: span}
+		nodes[span.SpanID] = node
+		ordered = append(ordered, node)
+
+		if span.IsRoot() {
+			if root != nil {
+				return nil, fmt.Errorf("%w: %s and %s", ErrMultipleRootSpans, root.Span.SpanID, span.SpanID)
+			}
+			root = node
+		}
+	}
+	if root == nil {
+		return nil, ErrNoRootSpan
+	}
+
+	for _, node := range ordered {
+		if node == root {
+			continue
+		}
+		parent, ok := nodes[node.Span.ParentSpanID]
+		if !ok {
+			node.Orphaned = true
+			parent = root
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return root, nil
+}
@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildTree_ThreeLevels(t *testing.T) {
+	spans := []*Span{
+		{SpanID: "grandchild", ParentSpanID: "child-1"},
+		{SpanID: "child-1", ParentSpanID: "root"},
+		{SpanID: "root"},
+		{SpanID: "child-2", ParentSpanID: "root"},
+	}
+
+	root, err := BuildTree(spans)
+	if err != nil {
+		t.Fatalf("BuildTree returned error: %v", err)
+	}
+	if root.Span.SpanID != "root" {
+		t.Fatalf("root = %q, want root", root.Span.SpanID)
+	}
+	if len(root.Children) != 2 || root.Children[0].Span.SpanID != "child-1" || root.Children[1].Span.SpanID != "child-2" {
+		t.Fatalf("unexpected root children: %+v", root.Children)
+	}
+	child := root.Children[0]
+	if len(child.Children) != 1 || child.Children[0].Span.SpanID != "grandchild" {
+		t.Fatalf("unexpected child-1 children: %+v", child.Children)
+	}
+	if len(root.Children[1].Children) != 0 {
+		t.Fatalf("expected child-2 to be a leaf")
+	}
+}
+
+func TestBuildTree_MissingRoot(t *testing.T) {
+	_, err := BuildTree([]*Span{{SpanID: "a", ParentSpanID: "x"}})
+	if !errors.Is(err, ErrNoRootSpan) {
+		t.Fatalf("error = %v, want %v", err, ErrNoRootSpan)
+	}
+
+	_, err = BuildTree(nil)
+	if !errors.Is(err, ErrNoRootSpan) {
+		t.Fatalf("error for empty input = %v, want %v", err, ErrNoRootSpan)
+	}
+}
+
+func TestBuildTree_MultipleRoots(t *testing.T) {
+	_, err := BuildTree([]*Span{{SpanID: "a"}, {SpanID: "b"}})
+	if !errors.Is(err, ErrMultipleRootSpans) {
+		t.Fatalf("error = %v, want %v", err, ErrMultipleRootSpans)
+	}
+}
+
+func TestBuildTree_OrphanAttachedToRoot(t *testing.T) {
+	root, err := BuildTree([]*Span{
+		{SpanID: "root"},
+		{SpanID: "orphan", ParentSpanID: "dropped"},
+	})
+	if err != nil {
+		t.Fatalf("BuildTree returned error: %v", err)
+	}
+	if len(root.Children) != 1 {
+		t.Fatalf("root children = %d, want 1", len(root.Children))
+	}
+	orphan := root.Children[0]
+	if orphan.Span.SpanID != "orphan" || !orphan.Orphaned {
+		t.Fatalf("unexpected orphan node: %+v", orphan)
+	}
+}
+
+func TestBuildTree_RootIsTheIsRootSpan(t *testing.T) {
+	spans := []*Span{
+		{SpanID: "b", ParentSpanID: "a"},
+		{SpanID: "a"},
+		{SpanID: "c", ParentSpanID: "b"},
+	}
+
+	root, err := BuildTree(spans)
+	if err != nil {
+		t.Fatalf("BuildTree returned error: %v", err)
+	}
+	if !root.Span.IsRoot() || root.Span.SpanID != "a" {
+		t.Fatalf("root = %s, want the span whose IsRoot is true", root.Span.SpanID)
+	}
+	for _, child := range root.Children {
+		if child.Span.IsRoot() {
+			t.Fatalf("child %s reports IsRoot", child.Span.SpanID)
+		}
+	}
+}
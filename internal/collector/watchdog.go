@@ -0,0 +1,49 @@
+package collector
+
+import "time"
+
+const deadlineExceededDescription = "deadline exceeded"
+
+// StartDeadline arms a watchdog that ends the span with StatusError and
+// "deadline exceeded" if End has not been called within timeout. A normal End
+// disarms it. Calling StartDeadline again replaces the previous deadline.
+func (s *Span) StartDeadline(timeout time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	if s.watchdog != nil {
+		s.watchdog.Stop()
+	}
+	// Stop cannot recall an expire that already fired and is waiting on s.mu,
+	// so each deadline gets a generation and only the current one may expire.
+	s.watchdogGen++
+	gen := s.watchdogGen
+	s.watchdog = time.AfterFunc(timeout, func() { s.expire(gen) })
+}
+
+func (s *Span) expire(gen uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended || gen != s.watchdogGen {
+		return
+	}
+	s.Status = StatusError
+	s.StatusDescription = deadlineExceededDescription
+	_ = s.endLocked(s.now())
+}
+
+func (s *Span) Ended() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ended
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpanStartDeadline_EndInTimeDisarmsWatchdog(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.StartDeadline(20 * time.Millisecond)
+	if err := span.End(); err != nil {
+		t.Fatalf("End returned error: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	if span.Status != StatusUnset {
+		t.Fatalf("status = %v, want %v", span.Status, StatusUnset)
+	}
+	if span.watchdog != nil {
+		t.Fatalf("expected watchdog to be cleared after End")
+	}
+}
+
+func TestSpanStartDeadline_TripsOnStuckSpan(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.StartDeadline(10 * time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for !span.Ended() {
+		if time.Now().After(deadline) {
+			t.Fatalf("watchdog did not end the span")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	span.mu.Lock()
+	status, description, duration := span.Status, span.StatusDescription, span.DurationNanos
+	span.mu.Unlock()
+	if status != StatusError || description != "deadline exceeded" {
+		t.Fatalf("status = %v %q, want error %q", status, description, "deadline exceeded")
+	}
+	if duration < int64(10*time.Millisecond) {
+		t.Fatalf("duration = %v, want >= 10ms", time.Duration(duration))
+	}
+	if err := span.End(); err != ErrSpanAlreadyEnded {
+		t.Fatalf("End after watchdog = %v, want %v", err, ErrSpanAlreadyEnded)
+	}
+}
+
+func TestSpanStartDeadline_ReplacedDeadlineIgnoresStaleExpiry(t *testing.T) {
+	span, err := NewSpan("inference-api", "predict", "gpt-4o-mini")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	span.StartDeadline(time.Hour)
+	span.mu.Lock()
+	stale := span.watchdogGen
+	span.mu.Unlock()
+	span.StartDeadline(time.Hour)
+
+	// An expiry of the first timer that fired before it was stopped.
+	span.expire(stale)
+	if span.Ended() {
+		t.Fatalf("stale expiry ended the span despite the new deadline")
+	}
+
+	span.mu.Lock()
+	current := span.watchdogGen
+	span.mu.Unlock()
+	span.expire(current)
+	if !span.Ended() {
+		t.Fatalf("current expiry did not end the span")
+	}
+}
@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// zipkinSpan is the Zipkin v2 JSON span model. Timestamps are microseconds.
+type zipkinSpan struct {
+	ID            string            `json:"id"`
+	TraceID       string            `json:"traceId"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint zipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+type ZipkinExporter struct {
+	poster *httpPoster
+}
+
+// NewZipkinExporter posts to endpoint, usually http://host:9411/api/v2/spans.
+// A nil client uses one with a 10s timeout.
+func NewZipkinExporter(endpoint string, client *http.Client, opts ...HTTPExportOption) *ZipkinExporter {
+	return &ZipkinExporter{
+		poster: newHTTPPoster("zipkin", endpoint, client, opts),
+	}
+}
+
+func (e *ZipkinExporter) Export(ctx context.Context, spans []*Span) error {
+	payload := make([]zipkinSpan, 0, len(spans))
+	for _, span := range spans {
+		if span == nil {
+			continue
+		}
+		payload = append(payload, toZipkinSpan(span))
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return e.poster.postJSON(ctx, body)
+}
+
+func toZipkinSpan(span *Span) zipkinSpan {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	out := zipkinSpan{
+		ID:            span.SpanID,
+		TraceID:       span.TraceID,
+		ParentID:      span.ParentSpanID,
+		Name:          span.OperationName,
+		Kind:          zipkinKind(span.Kind),
+		Timestamp:     span.StartTimeUnixNano / int64(time.Microsecond),
+		Duration:      span.DurationNanos / int64(time.Microsecond),
+		LocalEndpoint: zipkinEndpoint{ServiceName: span.ServiceName},
+	}
+
+	tags := make(map[string]string, len(span.Attributes)+1)
+	for key, value := range span.Attributes {
+		tags[key] = fmt.Sprint(value)
+	}
+	if span.ModelName != "" {
+		tags[AttrLLMModel] = span.ModelName
+	}
+	if span.Status == StatusError {
+		// Zipkin UIs highlight any span carrying an "error" tag.
+		tags["error"] = span.StatusDescription
+	}
+	if len(tags) > 0 {
+		out.Tags = tags
+	}
+	return out
+}
+
+func zipkinKind(kind SpanKind) string {
+	switch kind {
+	case SpanKindServer:
+		return "SERVER"
+	case SpanKindClient:
+		return "CLIENT"
+	case SpanKindProducer:
+		return "PRODUCER"
+	case SpanKindConsumer:
+		return "CONSUMER"
+	default:
+		return ""
+	}
+}
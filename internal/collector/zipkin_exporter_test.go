@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestZipkinExporter_PostsV2JSON(t *testing.T) {
+	var body []byte
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	exporter := NewZipkinExporter(srv.URL+"/api/v2/spans", srv.Client())
+	spans := []*Span{
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "00f067aa0ba902b7",
+			ServiceName:       "inference-api",
+			OperationName:     "predict",
+			Kind:              SpanKindServer,
+			StartTimeUnixNano: 1_700_000_000_123_456_789,
+			DurationNanos:     2_500_999,
+			Attributes:        map[string]any{"gpu.id": "gpu-0", "llm.usage.prompt_tokens": int64(12)},
+		},
+		{
+			TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+			SpanID:            "b7ad6b7169203331",
+			ParentSpanID:      "00f067aa0ba902b7",
+			ServiceName:       "tokenizer",
+			OperationName:     "encode",
+			Kind:              SpanKindInternal,
+			StartTimeUnixNano: 1_700_000_000_124_000_000,
+			DurationNanos:     1_000,
+			Status:            StatusError,
+			StatusDescription: "bad input",
+		},
+	}
+
+	if err := exporter.Export(context.Background(), spans); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if contentType != "application/json" {
+		t.Fatalf("content type = %q, want application/json", contentType)
+	}
+
+	want := `[
+		{
+			"id": "00f067aa0ba902b7",
+			"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"name": "predict",
+			"kind": "SERVER",
+			"timestamp": 1700000000123456,
+			"duration": 2500,
+			"localEndpoint": {"serviceName": "inference-api"},
+			"tags": {"gpu.id": "gpu-0", "llm.usage.prompt_tokens": "12"}
+		},
+		{
+			"id": "b7ad6b7169203331",
+			"traceId": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"parentId": "00f067aa0ba902b7",
+			"name": "encode",
+			"timestamp": 1700000000124000,
+			"duration": 1,
+			"localEndpoint": {"serviceName": "tokenizer"},
+			"tags": {"error": "bad input"}
+		}
+	]`
+	assertJSONEqual(t, body, []byte(want))
+}
+
+func TestZipkinExporter_NonSuccessStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	exporter := NewZipkinExporter(srv.URL, srv.Client())
+	if err := exporter.Export(context.Background(), []*Span{{SpanID: "a"}}); err == nil {
+		t.Fatalf("expected error for 500 response")
+	}
+}
+
+func TestZipkinExporter_ValidJSONArray(t *testing.T) {
+	var decoded []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&decoded)
+	}))
+	defer srv.Close()
+
+	exporter := NewZipkinExporter(srv.URL, srv.Client())
+	if err := exporter.Export(context.Background(), []*Span{{SpanID: "a"}, {SpanID: "b"}}); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("decoded %d spans, want 2", len(decoded))
+	}
+}
+
+func TestZipkinExporter_CancelledContextAbortsExport(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	exporter := NewZipkinExporter(srv.URL, srv.Client())
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := exporter.Export(ctx, []*Span{{SpanID: "a"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Export error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Export took %v after cancel, want prompt return", elapsed)
+	}
+}